@@ -4,11 +4,16 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"slices"
 
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	awsx "github.com/sachamama/sacha/internal/aws"
 	"github.com/sachamama/sacha/internal/config"
+	"github.com/sachamama/sacha/internal/demo"
+	"github.com/sachamama/sacha/internal/history"
+	"github.com/sachamama/sacha/internal/keymap"
+	"github.com/sachamama/sacha/internal/theme"
 	appui "github.com/sachamama/sacha/internal/ui/app"
 	logsui "github.com/sachamama/sacha/internal/ui/logs"
 
@@ -21,6 +26,8 @@ type cliFlags struct {
 	region  string
 	service string
 	verbose bool
+	files   []string
+	demo    bool
 }
 
 func main() {
@@ -45,6 +52,9 @@ func newRootCmd() *cobra.Command {
 	cmd.PersistentFlags().StringVar(&flags.region, "region", "", "AWS region")
 	cmd.PersistentFlags().StringVar(&flags.service, "service", "", "AWS service (cloudwatch-logs)")
 	cmd.PersistentFlags().BoolVar(&flags.verbose, "verbose", false, "enable verbose logging")
+	cmd.PersistentFlags().StringArrayVar(&flags.files, "file", nil, "open a local log file (repeatable)")
+	cmd.PersistentFlags().BoolVar(&flags.demo, "demo", false, "use generated demo data instead of AWS")
+	cmd.PersistentFlags().BoolVar(&flags.demo, "offline", false, "alias for --demo")
 
 	return cmd
 }
@@ -65,15 +75,35 @@ func run(ctx context.Context, flags cliFlags) error {
 	if err != nil {
 		return err
 	}
+	keys, err := keymap.New(fileCfg.Keys, slices.Concat(appui.KeyGroups, logsui.KeyGroups)...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", cfgPath, err)
+	}
+	th, err := theme.Load(fileCfg.Theme, fileCfg.Colors, os.Getenv("NO_COLOR") != "")
+	if err != nil {
+		return fmt.Errorf("%s: %w", cfgPath, err)
+	}
+
+	histPath := config.HistoryPath(cfgPath)
+	hist, err := history.Load(histPath)
+	if err != nil {
+		log.Warn().Err(err).Msg("starting with empty history")
+		hist = history.New(histPath)
+	}
 
 	envCfg := config.FromEnv()
 	runtime := config.Resolve(config.Flags{
 		Profile: flags.profile,
 		Region:  flags.region,
 		Service: flags.service,
+		Files:   flags.files,
+		Demo:    flags.demo,
 	}, envCfg, fileCfg)
 
 	loader := awsx.NewLoader()
+	if runtime.Demo {
+		loader = awsx.NewDemoLoader(demo.Region)
+	}
 
 	awsCfg, err := loader.Load(ctx, runtime.Profile, runtime.Region)
 	if err != nil {
@@ -87,12 +117,12 @@ func run(ctx context.Context, flags cliFlags) error {
 		"cloudwatch-logs": logsui.CloudWatchLogsService{},
 	}
 
-	appModel, err := appui.NewModel(loader, services, runtime, awsCfg, &log.Logger)
+	appModel, err := appui.NewModel(loader, services, runtime, awsCfg, fileCfg, hist, keys, th, &log.Logger)
 	if err != nil {
 		return err
 	}
 
-	p := tea.NewProgram(appModel, tea.WithAltScreen())
+	p := tea.NewProgram(appModel, tea.WithAltScreen(), tea.WithMouseCellMotion())
 	result, err := p.Run()
 	if err != nil {
 		return err
@@ -102,7 +132,10 @@ func run(ctx context.Context, flags cliFlags) error {
 		runtime = finalModel.Runtime()
 	}
 
-	fileCfg.LastRegion = runtime.Region
-	fileCfg.LastService = runtime.Service
+	// Demo sessions keep display settings but not the region and service.
+	if !runtime.Demo {
+		fileCfg.LastRegion = runtime.Region
+		fileCfg.LastService = runtime.Service
+	}
 	return config.Save(cfgPath, fileCfg)
 }
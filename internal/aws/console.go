@@ -0,0 +1,90 @@
+package awsx
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ConsoleHost returns the AWS console host serving region, accounting for
+// the China and GovCloud partitions.
+func ConsoleHost(region string) string {
+	switch {
+	case strings.HasPrefix(region, "cn-"):
+		return "console.amazonaws.cn"
+	case strings.HasPrefix(region, "us-gov-"):
+		return "console.amazonaws-us-gov.com"
+	default:
+		return region + ".console.aws.amazon.com"
+	}
+}
+
+// LogGroupURL links to a log group in the CloudWatch console.
+func LogGroupURL(region, group string) string {
+	return logsConsole(region, "log-groups/log-group/"+consoleName(group))
+}
+
+// LogStreamURL links to a log stream. A non-zero at opens the stream at
+// that time.
+func LogStreamURL(region, group, stream string, at time.Time) string {
+	fragment := "log-groups/log-group/" + consoleName(group) + "/log-events/" + consoleName(stream)
+	if !at.IsZero() {
+		fragment += consoleEscape(encodeURIComponent(fmt.Sprintf("?start=%d", at.UnixMilli())))
+	}
+	return logsConsole(region, fragment)
+}
+
+// InsightsURL links to Logs Insights with the query, log groups and an
+// absolute time range filled in.
+func InsightsURL(region string, groups []string, query string, start, end time.Time) string {
+	var sources strings.Builder
+	for _, g := range groups {
+		sources.WriteString("~'" + insightsEscape(g))
+	}
+	detail := fmt.Sprintf("~(end~'%s~start~'%s~timeType~'ABSOLUTE~tz~'UTC~editorString~'%s~source~(%s))",
+		insightsEscape(end.UTC().Format(time.RFC3339)),
+		insightsEscape(start.UTC().Format(time.RFC3339)),
+		insightsEscape(query),
+		sources.String())
+	return logsConsole(region, "logs-insights"+consoleEscape(encodeURIComponent("?queryDetail="+detail)))
+}
+
+func logsConsole(region, fragment string) string {
+	return fmt.Sprintf("https://%s/cloudwatch/home?region=%s#logsV2:%s", ConsoleHost(region), region, fragment)
+}
+
+// consoleEscape applies the console's fragment encoding, which writes
+// percent escapes with a dollar sign.
+func consoleEscape(s string) string {
+	return strings.ReplaceAll(s, "%", "$")
+}
+
+// consoleName encodes a group or stream name, which the console escapes
+// twice.
+func consoleName(name string) string {
+	return consoleEscape(encodeURIComponent(encodeURIComponent(name)))
+}
+
+// insightsEscape encodes a value inside the Logs Insights query detail,
+// which writes percent escapes with an asterisk.
+func insightsEscape(s string) string {
+	return strings.ReplaceAll(encodeURIComponent(s), "%", "*")
+}
+
+// encodeURIComponent matches the JavaScript function the console uses:
+// everything but letters, digits and -_.!~*'() is percent-encoded.
+func encodeURIComponent(s string) string {
+	const hex = "0123456789ABCDEF"
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || strings.IndexByte("-_.!~*'()", c) >= 0 {
+			b.WriteByte(c)
+			continue
+		}
+		b.WriteByte('%')
+		b.WriteByte(hex[c>>4])
+		b.WriteByte(hex[c&15])
+	}
+	return b.String()
+}
@@ -0,0 +1,37 @@
+package awsx
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLogGroupURL(t *testing.T) {
+	got := LogGroupURL("eu-west-1", "/aws/lambda/checkout")
+	want := "https://eu-west-1.console.aws.amazon.com/cloudwatch/home?region=eu-west-1#logsV2:log-groups/log-group/$252Faws$252Flambda$252Fcheckout"
+	if got != want {
+		t.Fatalf("got  %s\nwant %s", got, want)
+	}
+	if got := LogGroupURL("cn-north-1", "g"); got != "https://console.amazonaws.cn/cloudwatch/home?region=cn-north-1#logsV2:log-groups/log-group/g" {
+		t.Fatalf("unexpected China URL %s", got)
+	}
+}
+
+func TestLogStreamURL(t *testing.T) {
+	at := time.UnixMilli(1714557600123)
+	got := LogStreamURL("us-east-1", "/ecs/orders-api", "web/1 [a]", at)
+	want := "https://us-east-1.console.aws.amazon.com/cloudwatch/home?region=us-east-1#logsV2:log-groups/log-group/$252Fecs$252Forders-api/log-events/web$252F1$2520$255Ba$255D$3Fstart$3D1714557600123"
+	if got != want {
+		t.Fatalf("got  %s\nwant %s", got, want)
+	}
+}
+
+func TestInsightsURL(t *testing.T) {
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	got := InsightsURL("us-east-1", []string{"/aws/lambda/a", "/aws/lambda/b"}, "fields @message | limit 20", start, start.Add(time.Hour))
+	want := "https://us-east-1.console.aws.amazon.com/cloudwatch/home?region=us-east-1#logsV2:logs-insights" +
+		"$3FqueryDetail$3D~(end~'2024-05-01T11*3A00*3A00Z~start~'2024-05-01T10*3A00*3A00Z~timeType~'ABSOLUTE~tz~'UTC" +
+		"~editorString~'fields*20*40message*20*7C*20limit*2020~source~(~'*2Faws*2Flambda*2Fa~'*2Faws*2Flambda*2Fb))"
+	if got != want {
+		t.Fatalf("got  %s\nwant %s", got, want)
+	}
+}
@@ -0,0 +1,160 @@
+package awsx
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+)
+
+// ProfileType says where a profile gets its credentials.
+type ProfileType string
+
+const (
+	ProfileSSO     ProfileType = "sso"
+	ProfileRole    ProfileType = "role"
+	ProfileStatic  ProfileType = "static"
+	ProfileProcess ProfileType = "process"
+	// ProfileOther profiles only set options such as the region; the SDK
+	// finds credentials elsewhere, e.g. in the environment.
+	ProfileOther ProfileType = "other"
+)
+
+// Profile is a named profile of the shared AWS config and credentials files.
+type Profile struct {
+	Name   string
+	Type   ProfileType
+	Region string
+	// Detail identifies what the credentials lead to: the account and role
+	// of an SSO profile, or the role ARN of a role profile.
+	Detail string
+}
+
+// Profiles lists the profiles the loader can load, sorted by name.
+func (l Loader) Profiles() ([]Profile, error) {
+	if l.profiles == nil {
+		return nil, nil
+	}
+	return l.profiles()
+}
+
+// sharedProfiles reads the shared files where the SDK looks for them.
+func sharedProfiles() ([]Profile, error) {
+	return ReadProfiles(
+		envOr("AWS_CONFIG_FILE", config.DefaultSharedConfigFilename()),
+		envOr("AWS_SHARED_CREDENTIALS_FILE", config.DefaultSharedCredentialsFilename()))
+}
+
+func envOr(name, fallback string) string {
+	if v := os.Getenv(name); v != "" {
+		return v
+	}
+	return fallback
+}
+
+// ReadProfiles parses the shared config and credentials files. Missing files
+// have no profiles. A profile in both files combines their settings.
+func ReadProfiles(configPath, credentialsPath string) ([]Profile, error) {
+	settings := map[string]map[string]string{}
+	if err := readINI(configPath, configProfile, settings); err != nil {
+		return nil, err
+	}
+	if err := readINI(credentialsPath, credentialsProfile, settings); err != nil {
+		return nil, err
+	}
+	profiles := make([]Profile, 0, len(settings))
+	for name, kv := range settings {
+		profiles = append(profiles, profileFrom(name, kv))
+	}
+	slices.SortFunc(profiles, func(a, b Profile) int { return strings.Compare(a.Name, b.Name) })
+	return profiles, nil
+}
+
+func profileFrom(name string, kv map[string]string) Profile {
+	p := Profile{Name: name, Region: kv["region"], Type: ProfileOther}
+	switch {
+	case kv["sso_session"] != "" || kv["sso_start_url"] != "":
+		p.Type = ProfileSSO
+		if kv["sso_account_id"] != "" {
+			p.Detail = kv["sso_account_id"] + "/" + kv["sso_role_name"]
+		}
+	case kv["role_arn"] != "":
+		p.Type = ProfileRole
+		p.Detail = kv["role_arn"]
+	case kv["credential_process"] != "":
+		p.Type = ProfileProcess
+	case kv["aws_access_key_id"] != "":
+		p.Type = ProfileStatic
+	}
+	return p
+}
+
+// readINI adds the sections of an AWS shared file that section names to
+// settings; section returns false for the sections to skip.
+func readINI(path string, section func(header string) (string, bool), settings map[string]map[string]string) error {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read profiles: %w", err)
+	}
+	defer f.Close()
+
+	var kv map[string]string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		raw := scanner.Text()
+		line := strings.TrimSpace(raw)
+		switch {
+		case line == "" || line[0] == '#' || line[0] == ';':
+		case raw[0] == ' ' || raw[0] == '\t':
+			// Indented lines hold nested values, e.g. s3 settings.
+		case line[0] == '[':
+			kv = nil
+			name, ok := section(strings.Trim(line, "[] \t"))
+			if !ok {
+				continue
+			}
+			if settings[name] == nil {
+				settings[name] = map[string]string{}
+			}
+			kv = settings[name]
+		case kv != nil:
+			key, value, ok := strings.Cut(line, "=")
+			if ok {
+				kv[strings.TrimSpace(key)] = strings.TrimSpace(value)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("read profiles from %s: %w", path, err)
+	}
+	return nil
+}
+
+// credentialsProfile names the sections of the credentials file, which are
+// all profiles.
+func credentialsProfile(header string) (string, bool) {
+	return header, header != ""
+}
+
+// configProfile names the profile sections of the config file. Profiles
+// other than default are written "[profile name]"; other sections, such as
+// sso-session, are skipped.
+func configProfile(header string) (string, bool) {
+	if header == "default" {
+		return header, true
+	}
+	return prefixedSection(header, "profile ")
+}
+
+func prefixedSection(header, prefix string) (string, bool) {
+	name, ok := strings.CutPrefix(header, prefix)
+	name = strings.TrimSpace(name)
+	return name, ok && name != ""
+}
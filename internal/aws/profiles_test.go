@@ -0,0 +1,96 @@
+package awsx
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestReadProfiles(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config")
+	credentialsPath := filepath.Join(dir, "credentials")
+	writeFile(t, configPath, `
+[default]
+region = eu-west-1
+
+[profile dev]
+sso_session = corp
+sso_account_id = 111111111111
+sso_role_name = Developer
+region = eu-central-1
+
+[sso-session corp]
+sso_start_url = https://corp.awsapps.com/start
+sso_region = eu-west-1
+
+# role assumed from the default credentials
+[profile prod]
+role_arn = arn:aws:iam::222222222222:role/ReadOnly
+source_profile = default
+s3 =
+  max_concurrent_requests = 20
+
+[profile legacy-sso]
+sso_start_url = https://old.awsapps.com/start
+
+[profile vault]
+credential_process = aws-vault export --format=json vault
+`)
+	writeFile(t, credentialsPath, `
+[default]
+aws_access_key_id = AKIAEXAMPLE
+aws_secret_access_key = secret
+
+; only in the credentials file
+[ci]
+aws_access_key_id = AKIAEXAMPLE2
+aws_secret_access_key = secret2
+`)
+
+	got, err := ReadProfiles(configPath, credentialsPath)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	want := []Profile{
+		{Name: "ci", Type: ProfileStatic},
+		{Name: "default", Type: ProfileStatic, Region: "eu-west-1"},
+		{Name: "dev", Type: ProfileSSO, Region: "eu-central-1", Detail: "111111111111/Developer"},
+		{Name: "legacy-sso", Type: ProfileSSO},
+		{Name: "prod", Type: ProfileRole, Detail: "arn:aws:iam::222222222222:role/ReadOnly"},
+		{Name: "vault", Type: ProfileProcess},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got  %+v\nwant %+v", got, want)
+	}
+}
+
+func TestReadProfilesWithoutFiles(t *testing.T) {
+	dir := t.TempDir()
+	got, err := ReadProfiles(filepath.Join(dir, "config"), filepath.Join(dir, "credentials"))
+	if err != nil || len(got) != 0 {
+		t.Fatalf("expected no profiles and no error, got %v, %v", got, err)
+	}
+}
+
+func TestDemoLoaderProfilesLoad(t *testing.T) {
+	loader := NewDemoLoader("us-east-1")
+	profiles, err := loader.Profiles()
+	if err != nil || len(profiles) == 0 {
+		t.Fatalf("expected demo profiles, got %v, %v", profiles, err)
+	}
+	for _, p := range profiles {
+		if _, err := loader.Load(context.Background(), p.Name, p.Region); err != nil {
+			t.Fatalf("load %s: %v", p.Name, err)
+		}
+	}
+}
+
+func writeFile(t *testing.T, path, data string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
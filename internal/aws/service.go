@@ -2,9 +2,14 @@ package awsx
 
 import (
 	"context"
+	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/sachamama/sacha/internal/config"
+	"github.com/sachamama/sacha/internal/history"
+	"github.com/sachamama/sacha/internal/keymap"
+	"github.com/sachamama/sacha/internal/theme"
 )
 
 // Service defines a pluggable AWS-backed UI module.
@@ -17,6 +22,26 @@ type Service interface {
 // ServiceOptions contains dependencies shared with services.
 type ServiceOptions struct {
 	Logger ServiceLogger
+	// Loader builds additional AWS configs, e.g. for services spanning regions.
+	Loader Loader
+	// Profile is the active AWS profile; empty means the SDK default.
+	Profile string
+	// Config is the persisted user configuration. Services may update display
+	// preferences on it; the app saves it on exit. It may be nil.
+	Config *config.Config
+	// History records searches, filters and queries across sessions. It may
+	// be nil.
+	History *history.Store
+	// Files are local log files to open at start, for services that can
+	// read them.
+	Files []string
+	// Demo replaces AWS APIs with the in-memory fakes of package demo.
+	Demo bool
+	// Keys are the effective key bindings of the app and the service. Nil
+	// means the service's defaults.
+	Keys *keymap.Keymap
+	// Theme colors the views. Nil means the default theme.
+	Theme *theme.Theme
 }
 
 // ServiceLogger is a narrow logging interface used by services.
@@ -25,3 +50,32 @@ type ServiceLogger interface {
 	Info(msg string, kv ...interface{})
 	Error(msg string, kv ...interface{})
 }
+
+// Command is an action a service offers in the command palette.
+type Command struct {
+	// Name is what the user types, e.g. "tail".
+	Name string
+	// Description says what the command does, e.g. "tail the selected log groups".
+	Description string
+	// Key is the key binding of the same action; empty if it has none.
+	Key string
+	// Args describes the argument: "<region>" when required, "[group...]"
+	// when optional, empty when the command takes none.
+	Args string
+}
+
+// ArgsRequired reports whether the command cannot run without an argument.
+func (c Command) ArgsRequired() bool {
+	return strings.HasPrefix(c.Args, "<")
+}
+
+// CommandMsg asks a service to run one of its commands.
+type CommandMsg struct {
+	Name string
+	Args string
+}
+
+// Commander is implemented by services that offer commands.
+type Commander interface {
+	Commands() []Command
+}
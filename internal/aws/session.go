@@ -2,23 +2,71 @@ package awsx
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
 )
 
 type loadConfigFunc func(ctx context.Context, optFns ...func(*config.LoadOptions) error) (aws.Config, error)
 
 // Loader wraps AWS SDK configuration loading to allow injection in tests.
 type Loader struct {
-	load loadConfigFunc
+	load       loadConfigFunc
+	profiles   func() ([]Profile, error)
+	ssoSession func(profile string) (SSOSession, error)
+	oidc       func(region string) OIDCAPI
+	account    func(ctx context.Context, profile string, cfg aws.Config) (string, error)
 }
 
 // NewLoader returns a Loader that uses the default AWS SDK behavior.
 func NewLoader() Loader {
 	return Loader{
-		load: config.LoadDefaultConfig,
+		load:       config.LoadDefaultConfig,
+		profiles:   sharedProfiles,
+		ssoSession: sharedSSOSession,
+		oidc:       newOIDCClient,
+		account:    callerAccount,
+	}
+}
+
+// NewDemoLoader returns a Loader that never reads credentials or profiles.
+// Configs carry the requested region, or defaultRegion, and anonymous
+// credentials for use with the in-memory fakes. It offers a few made-up
+// profiles, which all load.
+func NewDemoLoader(defaultRegion string) Loader {
+	return Loader{
+		load: func(ctx context.Context, optFns ...func(*config.LoadOptions) error) (aws.Config, error) {
+			var opts config.LoadOptions
+			for _, fn := range optFns {
+				if err := fn(&opts); err != nil {
+					return aws.Config{}, err
+				}
+			}
+			region := opts.Region
+			if region == "" {
+				region = defaultRegion
+			}
+			return aws.Config{Region: region, Credentials: aws.AnonymousCredentials{}}, nil
+		},
+		profiles: func() ([]Profile, error) {
+			return []Profile{
+				{Name: "default", Type: ProfileStatic},
+				{Name: "demo-dev", Type: ProfileSSO, Region: "eu-west-1", Detail: "111111111111/Developer"},
+				{Name: "demo-prod", Type: ProfileRole, Region: "us-east-1", Detail: "arn:aws:iam::222222222222:role/ReadOnly"},
+			}, nil
+		},
+		account: func(ctx context.Context, profile string, cfg aws.Config) (string, error) {
+			switch profile {
+			case "demo-dev":
+				return "111111111111", nil
+			case "demo-prod":
+				return "222222222222", nil
+			}
+			return "123456789012", nil
+		},
 	}
 }
 
@@ -38,3 +86,20 @@ func (l Loader) Load(ctx context.Context, profile, region string) (aws.Config, e
 	}
 	return cfg, nil
 }
+
+// AccountID returns the AWS account the credentials of cfg, loaded for
+// profile, belong to.
+func (l Loader) AccountID(ctx context.Context, profile string, cfg aws.Config) (string, error) {
+	if l.account == nil {
+		return "", errors.New("resolve account: not supported by this loader")
+	}
+	return l.account(ctx, profile, cfg)
+}
+
+func callerAccount(ctx context.Context, profile string, cfg aws.Config) (string, error) {
+	out, err := sts.NewFromConfig(cfg).GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return "", fmt.Errorf("resolve account: %w", err)
+	}
+	return aws.ToString(out.Account), nil
+}
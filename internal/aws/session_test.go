@@ -2,10 +2,14 @@ package awsx
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
 )
 
 func TestLoaderAppliesRegionAndProfile(t *testing.T) {
@@ -32,3 +36,62 @@ func TestLoaderAppliesRegionAndProfile(t *testing.T) {
 		t.Fatalf("region not applied, got %s", captured.Region)
 	}
 }
+
+func TestDemoLoaderNeedsNoCredentials(t *testing.T) {
+	loader := NewDemoLoader("us-east-1")
+
+	cfg, err := loader.Load(context.Background(), "no-such-profile", "")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if cfg.Region != "us-east-1" {
+		t.Fatalf("expected the demo region, got %s", cfg.Region)
+	}
+	if _, ok := cfg.Credentials.(aws.AnonymousCredentials); !ok {
+		t.Fatalf("expected anonymous credentials, got %T", cfg.Credentials)
+	}
+
+	cfg, _ = loader.Load(context.Background(), "", "eu-west-1")
+	if cfg.Region != "eu-west-1" {
+		t.Fatalf("region not applied, got %s", cfg.Region)
+	}
+}
+
+func TestAccountIDCallsSTS(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.Form.Get("Action") != "GetCallerIdentity" {
+			http.Error(w, "unexpected action", http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "text/xml")
+		fmt.Fprint(w, `<GetCallerIdentityResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <GetCallerIdentityResult>
+    <Arn>arn:aws:iam::333333333333:user/dev</Arn>
+    <UserId>AIDAEXAMPLE</UserId>
+    <Account>333333333333</Account>
+  </GetCallerIdentityResult>
+  <ResponseMetadata><RequestId>1</RequestId></ResponseMetadata>
+</GetCallerIdentityResponse>`)
+	}))
+	defer srv.Close()
+	cfg := aws.Config{
+		Region:       "eu-west-1",
+		Credentials:  credentials.NewStaticCredentialsProvider("AKID", "SECRET", ""),
+		BaseEndpoint: aws.String(srv.URL),
+	}
+	account, err := NewLoader().AccountID(context.Background(), "dev", cfg)
+	if err != nil || account != "333333333333" {
+		t.Fatalf("AccountID = %q, %v", account, err)
+	}
+}
+
+func TestDemoLoaderAccounts(t *testing.T) {
+	loader := NewDemoLoader("us-east-1")
+	for profile, want := range map[string]string{"demo-dev": "111111111111", "demo-prod": "222222222222", "": "123456789012"} {
+		cfg, _ := loader.Load(context.Background(), profile, "")
+		if got, err := loader.AccountID(context.Background(), profile, cfg); err != nil || got != want {
+			t.Fatalf("%q: AccountID = %q, %v, want %s", profile, got, err, want)
+		}
+	}
+}
@@ -0,0 +1,307 @@
+package awsx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/ssocreds"
+	"github.com/aws/aws-sdk-go-v2/service/ssooidc"
+	"github.com/aws/aws-sdk-go-v2/service/ssooidc/types"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	deviceCodeGrant = "urn:ietf:params:oauth:grant-type:device_code"
+	// defaultSSOScope lets the SDK refresh the tokens of sso-session
+	// profiles, as the AWS CLI asks for it too.
+	defaultSSOScope = "sso:account:access"
+)
+
+// SSOLoginMsg asks the app to sign profile in to its SSO session. Services
+// send it when a call fails for want of a valid SSO token; the app runs
+// Retry once the login succeeds.
+type SSOLoginMsg struct {
+	Profile string
+	Retry   tea.Cmd
+}
+
+// IsSSOTokenError reports whether err comes from a missing or expired SSO
+// token, which a new SSO login fixes.
+func IsSSOTokenError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var invalid *ssocreds.InvalidTokenError
+	if errors.As(err, &invalid) {
+		return true
+	}
+	// The token provider of sso-session profiles returns untyped errors.
+	return strings.Contains(err.Error(), "cached SSO token")
+}
+
+// SSOSession holds where a profile signs in to IAM Identity Center.
+type SSOSession struct {
+	// Name is the sso-session the profile refers to; it is empty for
+	// legacy profiles that set sso_start_url themselves.
+	Name     string
+	StartURL string
+	Region   string
+	Scopes   []string
+}
+
+// cacheKey names the token in the SSO cache, as the SDK looks it up.
+func (s SSOSession) cacheKey() string {
+	if s.Name != "" {
+		return s.Name
+	}
+	return s.StartURL
+}
+
+// sharedSSOSession reads the SSO settings of profile from the shared config
+// file where the SDK looks for it.
+func sharedSSOSession(profile string) (SSOSession, error) {
+	if profile == "" {
+		profile = envOr("AWS_PROFILE", "default")
+	}
+	return ReadSSOSession(envOr("AWS_CONFIG_FILE", config.DefaultSharedConfigFilename()), profile)
+}
+
+// ReadSSOSession returns the SSO settings of profile in the shared config
+// file, from its sso-session section or from the profile itself.
+func ReadSSOSession(configPath, profile string) (SSOSession, error) {
+	profiles := map[string]map[string]string{}
+	if err := readINI(configPath, configProfile, profiles); err != nil {
+		return SSOSession{}, err
+	}
+	kv, ok := profiles[profile]
+	if !ok {
+		return SSOSession{}, fmt.Errorf("profile %q not found in %s", profile, configPath)
+	}
+	s := SSOSession{Name: kv["sso_session"]}
+	if s.Name != "" {
+		sessions := map[string]map[string]string{}
+		err := readINI(configPath, func(header string) (string, bool) {
+			return prefixedSection(header, "sso-session ")
+		}, sessions)
+		if err != nil {
+			return SSOSession{}, err
+		}
+		if kv, ok = sessions[s.Name]; !ok {
+			return SSOSession{}, fmt.Errorf("profile %q: sso-session %q not found in %s", profile, s.Name, configPath)
+		}
+		s.Scopes = []string{defaultSSOScope}
+		if scopes := kv["sso_registration_scopes"]; scopes != "" {
+			s.Scopes = nil
+			for _, scope := range strings.Split(scopes, ",") {
+				if scope = strings.TrimSpace(scope); scope != "" {
+					s.Scopes = append(s.Scopes, scope)
+				}
+			}
+		}
+	}
+	s.StartURL, s.Region = kv["sso_start_url"], kv["sso_region"]
+	if s.StartURL == "" {
+		return SSOSession{}, fmt.Errorf("profile %q does not use SSO", profile)
+	}
+	if s.Region == "" {
+		return SSOSession{}, fmt.Errorf("profile %q: no sso_region", profile)
+	}
+	return s, nil
+}
+
+// OIDCAPI is the part of the SSO OIDC API the device login uses.
+type OIDCAPI interface {
+	RegisterClient(ctx context.Context, params *ssooidc.RegisterClientInput, optFns ...func(*ssooidc.Options)) (*ssooidc.RegisterClientOutput, error)
+	StartDeviceAuthorization(ctx context.Context, params *ssooidc.StartDeviceAuthorizationInput, optFns ...func(*ssooidc.Options)) (*ssooidc.StartDeviceAuthorizationOutput, error)
+	CreateToken(ctx context.Context, params *ssooidc.CreateTokenInput, optFns ...func(*ssooidc.Options)) (*ssooidc.CreateTokenOutput, error)
+}
+
+func newOIDCClient(region string) OIDCAPI {
+	return ssooidc.New(ssooidc.Options{Region: region})
+}
+
+// SSOLogin is a device authorization waiting for the user to approve it in
+// a browser.
+type SSOLogin struct {
+	Session SSOSession
+	// URL opens the approval page with the code filled in.
+	URL string
+	// Code is shown on the approval page; the user checks that it matches.
+	Code    string
+	Expires time.Time
+
+	api          OIDCAPI
+	clientID     string
+	clientSecret string
+	clientExpiry time.Time
+	deviceCode   string
+	interval     time.Duration
+}
+
+// StartSSOLogin registers sacha as an OIDC client of the session and starts
+// a device authorization.
+func StartSSOLogin(ctx context.Context, api OIDCAPI, s SSOSession) (*SSOLogin, error) {
+	client, err := api.RegisterClient(ctx, &ssooidc.RegisterClientInput{
+		ClientName: aws.String("sacha"),
+		ClientType: aws.String("public"),
+		Scopes:     s.Scopes,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("sso login: register client: %w", err)
+	}
+	auth, err := api.StartDeviceAuthorization(ctx, &ssooidc.StartDeviceAuthorizationInput{
+		ClientId:     client.ClientId,
+		ClientSecret: client.ClientSecret,
+		StartUrl:     aws.String(s.StartURL),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("sso login: start device authorization: %w", err)
+	}
+	l := &SSOLogin{
+		Session:      s,
+		URL:          aws.ToString(auth.VerificationUriComplete),
+		Code:         aws.ToString(auth.UserCode),
+		api:          api,
+		clientID:     aws.ToString(client.ClientId),
+		clientSecret: aws.ToString(client.ClientSecret),
+		deviceCode:   aws.ToString(auth.DeviceCode),
+		interval:     time.Duration(max(auth.Interval, 1)) * time.Second,
+	}
+	if l.URL == "" {
+		l.URL = aws.ToString(auth.VerificationUri)
+	}
+	if auth.ExpiresIn > 0 {
+		l.Expires = time.Now().Add(time.Duration(auth.ExpiresIn) * time.Second)
+	}
+	if client.ClientSecretExpiresAt > 0 {
+		l.clientExpiry = time.Unix(client.ClientSecretExpiresAt, 0)
+	}
+	return l, nil
+}
+
+// Wait polls until the user approves the login, then writes the token to
+// the SSO cache where the SDK and the AWS CLI find it.
+func (l *SSOLogin) Wait(ctx context.Context) error {
+	parent := ctx
+	if !l.Expires.IsZero() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, l.Expires)
+		defer cancel()
+	}
+	interval := l.interval
+	for {
+		select {
+		case <-ctx.Done():
+			if err := parent.Err(); err != nil {
+				return err
+			}
+			return errors.New("sso login: the code expired before it was approved")
+		case <-time.After(interval):
+		}
+		out, err := l.api.CreateToken(ctx, &ssooidc.CreateTokenInput{
+			ClientId:     aws.String(l.clientID),
+			ClientSecret: aws.String(l.clientSecret),
+			DeviceCode:   aws.String(l.deviceCode),
+			GrantType:    aws.String(deviceCodeGrant),
+		})
+		var (
+			pending *types.AuthorizationPendingException
+			slow    *types.SlowDownException
+		)
+		switch {
+		case errors.As(err, &pending):
+			continue
+		case errors.As(err, &slow):
+			interval += 5 * time.Second
+			continue
+		case err != nil:
+			return fmt.Errorf("sso login: %w", err)
+		}
+		return l.writeToken(out, time.Now())
+	}
+}
+
+// cachedToken is the SSO cache file format shared by the SDKs and the AWS CLI.
+type cachedToken struct {
+	StartURL              string `json:"startUrl"`
+	Region                string `json:"region"`
+	AccessToken           string `json:"accessToken"`
+	ExpiresAt             string `json:"expiresAt"`
+	RefreshToken          string `json:"refreshToken,omitempty"`
+	ClientID              string `json:"clientId,omitempty"`
+	ClientSecret          string `json:"clientSecret,omitempty"`
+	RegistrationExpiresAt string `json:"registrationExpiresAt,omitempty"`
+}
+
+func (l *SSOLogin) writeToken(out *ssooidc.CreateTokenOutput, now time.Time) error {
+	path, err := ssocreds.StandardCachedTokenFilepath(l.Session.cacheKey())
+	if err != nil {
+		return fmt.Errorf("sso login: %w", err)
+	}
+	t := cachedToken{
+		StartURL:    l.Session.StartURL,
+		Region:      l.Session.Region,
+		AccessToken: aws.ToString(out.AccessToken),
+		ExpiresAt:   now.Add(time.Duration(out.ExpiresIn) * time.Second).UTC().Format(time.RFC3339),
+	}
+	// The SDK refreshes tokens of sso-session profiles with the client
+	// registration; legacy profiles cannot be refreshed.
+	if l.Session.Name != "" {
+		t.RefreshToken = aws.ToString(out.RefreshToken)
+		t.ClientID = l.clientID
+		t.ClientSecret = l.clientSecret
+		if !l.clientExpiry.IsZero() {
+			t.RegistrationExpiresAt = l.clientExpiry.UTC().Format(time.RFC3339)
+		}
+	}
+	data, err := json.Marshal(t)
+	if err != nil {
+		return fmt.Errorf("sso login: %w", err)
+	}
+	if err := writeFileAtomic(path, data); err != nil {
+		return fmt.Errorf("sso login: write token: %w", err)
+	}
+	return nil
+}
+
+// writeFileAtomic replaces path with data, readable by the user only.
+func writeFileAtomic(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(dir, ".sacha-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// StartSSOLogin starts the device login of profile's SSO session; an empty
+// profile means the SDK default.
+func (l Loader) StartSSOLogin(ctx context.Context, profile string) (*SSOLogin, error) {
+	if l.ssoSession == nil {
+		return nil, errors.New("sso login: demo profiles need no login")
+	}
+	s, err := l.ssoSession(profile)
+	if err != nil {
+		return nil, fmt.Errorf("sso login: %w", err)
+	}
+	return StartSSOLogin(ctx, l.oidc(s.Region), s)
+}
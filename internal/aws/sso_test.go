@@ -0,0 +1,202 @@
+package awsx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials/ssocreds"
+	"github.com/aws/aws-sdk-go-v2/service/ssooidc"
+)
+
+func TestReadSSOSession(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config")
+	writeFile(t, configPath, `
+[profile dev]
+sso_session = corp
+sso_account_id = 111111111111
+sso_role_name = Developer
+
+[sso-session corp]
+sso_start_url = https://corp.awsapps.com/start
+sso_region = eu-west-1
+sso_registration_scopes = sso:account:access, codewhisperer:completions
+
+[profile legacy]
+sso_start_url = https://old.awsapps.com/start
+sso_region = us-east-1
+
+[profile orphan]
+sso_session = gone
+
+[profile static]
+region = eu-west-1
+`)
+	got, err := ReadSSOSession(configPath, "dev")
+	if err != nil {
+		t.Fatalf("dev: %v", err)
+	}
+	want := SSOSession{Name: "corp", StartURL: "https://corp.awsapps.com/start", Region: "eu-west-1",
+		Scopes: []string{"sso:account:access", "codewhisperer:completions"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got  %+v\nwant %+v", got, want)
+	}
+
+	got, err = ReadSSOSession(configPath, "legacy")
+	if err != nil {
+		t.Fatalf("legacy: %v", err)
+	}
+	if want := (SSOSession{StartURL: "https://old.awsapps.com/start", Region: "us-east-1"}); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got  %+v\nwant %+v", got, want)
+	}
+
+	for _, profile := range []string{"orphan", "static", "missing"} {
+		if _, err := ReadSSOSession(configPath, profile); err == nil {
+			t.Fatalf("%s: expected an error", profile)
+		}
+	}
+}
+
+func TestIsSSOTokenError(t *testing.T) {
+	for _, tc := range []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{errors.New("AccessDeniedException: not authorized"), false},
+		{fmt.Errorf("failed to refresh cached credentials, %w", &ssocreds.InvalidTokenError{}), true},
+		{errors.New("refresh cached SSO token failed, unable to refresh SSO token, expired"), true},
+		{errors.New("failed to read cached SSO token file, open x.json: no such file or directory"), true},
+	} {
+		if got := IsSSOTokenError(tc.err); got != tc.want {
+			t.Fatalf("IsSSOTokenError(%v) = %v, want %v", tc.err, got, tc.want)
+		}
+	}
+}
+
+// oidcStandIn serves the SSO OIDC operations of the device flow. The token
+// is pending until approved is closed.
+func oidcStandIn(t *testing.T, approved <-chan struct{}) *httptest.Server {
+	t.Helper()
+	reply := func(w http.ResponseWriter, status int, v any) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(v)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /client/register", func(w http.ResponseWriter, r *http.Request) {
+		reply(w, http.StatusOK, map[string]any{
+			"clientId": "client-1", "clientSecret": "secret-1", "clientSecretExpiresAt": 1893456000,
+		})
+	})
+	mux.HandleFunc("POST /device_authorization", func(w http.ResponseWriter, r *http.Request) {
+		var in struct{ ClientID, StartURL string }
+		json.NewDecoder(r.Body).Decode(&in)
+		if in.ClientID != "client-1" || in.StartURL != "https://corp.awsapps.com/start" {
+			reply(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+			return
+		}
+		reply(w, http.StatusOK, map[string]any{
+			"deviceCode": "device-1", "userCode": "ABCD-EFGH", "expiresIn": 600, "interval": 1,
+			"verificationUri":         "https://device.sso.eu-west-1.amazonaws.com/",
+			"verificationUriComplete": "https://device.sso.eu-west-1.amazonaws.com/?user_code=ABCD-EFGH",
+		})
+	})
+	mux.HandleFunc("POST /token", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-approved:
+		default:
+			w.Header().Set("X-Amzn-ErrorType", "AuthorizationPendingException")
+			reply(w, http.StatusBadRequest, map[string]string{"error": "authorization_pending"})
+			return
+		}
+		reply(w, http.StatusOK, map[string]any{
+			"accessToken": "token-1", "refreshToken": "refresh-1", "tokenType": "Bearer", "expiresIn": 3600,
+		})
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestSSOLoginWritesCachedToken(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	approved := make(chan struct{})
+	srv := oidcStandIn(t, approved)
+	api := ssooidc.New(ssooidc.Options{Region: "eu-west-1", BaseEndpoint: aws.String(srv.URL)})
+	session := SSOSession{Name: "corp", StartURL: "https://corp.awsapps.com/start", Region: "eu-west-1", Scopes: []string{defaultSSOScope}}
+
+	login, err := StartSSOLogin(context.Background(), api, session)
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if login.Code != "ABCD-EFGH" || login.URL != "https://device.sso.eu-west-1.amazonaws.com/?user_code=ABCD-EFGH" {
+		t.Fatalf("unexpected authorization: %+v", login)
+	}
+	login.interval = 10 * time.Millisecond
+	time.AfterFunc(50*time.Millisecond, func() { close(approved) })
+	if err := login.Wait(context.Background()); err != nil {
+		t.Fatalf("wait: %v", err)
+	}
+
+	path, err := ssocreds.StandardCachedTokenFilepath("corp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("token not cached: %v", err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Fatalf("token file mode = %v, want 0600", info.Mode().Perm())
+	}
+	var got cachedToken
+	data, _ := os.ReadFile(path)
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if got.AccessToken != "token-1" || got.RefreshToken != "refresh-1" || got.ClientID != "client-1" || got.Region != "eu-west-1" {
+		t.Fatalf("unexpected token file: %s", data)
+	}
+	expires, err := time.Parse(time.RFC3339, got.ExpiresAt)
+	if err != nil || time.Until(expires) < 59*time.Minute {
+		t.Fatalf("expiresAt = %q, want about an hour from now", got.ExpiresAt)
+	}
+
+	// The SDK reads the new token back.
+	token, err := ssocreds.NewSSOTokenProvider(nil, path).RetrieveBearerToken(context.Background())
+	if err != nil || token.Value != "token-1" {
+		t.Fatalf("SDK does not accept the cached token: %v, %v", token, err)
+	}
+}
+
+func TestSSOLoginCancel(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	srv := oidcStandIn(t, make(chan struct{}))
+	api := ssooidc.New(ssooidc.Options{Region: "eu-west-1", BaseEndpoint: aws.String(srv.URL)})
+	login, err := StartSSOLogin(context.Background(), api, SSOSession{StartURL: "https://corp.awsapps.com/start", Region: "eu-west-1"})
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	login.interval = 10 * time.Millisecond
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := login.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the wait to end with the context, got %v", err)
+	}
+}
+
+func TestDemoLoaderHasNoSSOLogin(t *testing.T) {
+	if _, err := NewDemoLoader("us-east-1").StartSSOLogin(context.Background(), "demo-dev"); err == nil {
+		t.Fatalf("expected demo profiles to need no login")
+	}
+}
@@ -12,14 +12,56 @@ const (
 	defaultService = "cloudwatch-logs"
 	configDirName  = "sacha"
 	configFileName = "config.json"
+	historyName    = "history.json"
 )
 
+// Timestamp display modes accepted in Config.TimeDisplay.
+const (
+	TimeDisplayUTC      = "utc"
+	TimeDisplayLocal    = "local"
+	TimeDisplayZone     = "zone"
+	TimeDisplayRelative = "relative"
+)
+
+// DefaultTimeFormat is RFC3339 with millisecond precision.
+const DefaultTimeFormat = "2006-01-02T15:04:05.000Z07:00"
+
 // Config represents the persisted user configuration.
 type Config struct {
 	DefaultProfile string `json:"defaultProfile,omitempty"`
 	DefaultRegion  string `json:"defaultRegion,omitempty"`
 	LastRegion     string `json:"lastRegion,omitempty"`
 	LastService    string `json:"lastService,omitempty"`
+
+	// TimeDisplay is one of the TimeDisplay* modes; empty means local.
+	TimeDisplay string `json:"timeDisplay,omitempty"`
+	// TimeZone is the IANA zone used by TimeDisplayZone, e.g. "Europe/Berlin".
+	TimeZone string `json:"timeZone,omitempty"`
+	// TimeFormat is a Go time layout; empty means DefaultTimeFormat.
+	TimeFormat string `json:"timeFormat,omitempty"`
+
+	// CorrelationFields are JSON paths holding correlation IDs, e.g.
+	// "$.correlationId". Empty means a built-in default set.
+	CorrelationFields []string `json:"correlationFields,omitempty"`
+	// RelatedGroups names sets of log groups that serve the same requests.
+	// Following an ID from a group in a set searches the whole set.
+	RelatedGroups map[string][]string `json:"relatedGroups,omitempty"`
+
+	// SplitPercent is the share of the width given to the log group pane;
+	// 0 means half.
+	SplitPercent int `json:"splitPercent,omitempty"`
+	// Zoom shows only the focused pane, at full width.
+	Zoom bool `json:"zoom,omitempty"`
+
+	// Keys overrides key bindings by action name, e.g. {"tail": ["ctrl+t"]}.
+	// An empty list unbinds the action.
+	Keys map[string][]string `json:"keys,omitempty"`
+
+	// Theme is "dark" (the default), "light" or "high-contrast".
+	Theme string `json:"theme,omitempty"`
+	// Colors override colors of the theme by role, e.g. {"title": "#d75fd7"}.
+	// Values are ANSI 256 numbers or hex colors.
+	Colors map[string]string `json:"colors,omitempty"`
 }
 
 // RuntimeConfig resolves configuration after applying precedence rules.
@@ -27,6 +69,8 @@ type RuntimeConfig struct {
 	Profile string
 	Region  string
 	Service string
+	Files   []string
+	Demo    bool
 }
 
 // Flags captures CLI flag values.
@@ -34,6 +78,8 @@ type Flags struct {
 	Profile string
 	Region  string
 	Service string
+	Files   []string
+	Demo    bool
 }
 
 // Env captures supported environment variables.
@@ -58,6 +104,12 @@ func DefaultPath() (string, error) {
 	return filepath.Join(dir, configDirName, configFileName), nil
 }
 
+// HistoryPath returns the location of the history file, next to the config
+// file at cfgPath.
+func HistoryPath(cfgPath string) string {
+	return filepath.Join(filepath.Dir(cfgPath), historyName)
+}
+
 // Load reads the config file if present; a missing file is not an error.
 func Load(path string) (*Config, error) {
 	data, err := os.ReadFile(path)
@@ -132,6 +184,10 @@ func Resolve(flags Flags, env Env, cfg *Config) RuntimeConfig {
 		result.Service = defaultService
 	}
 
+	// Local files and demo mode are only given on the command line.
+	result.Files = flags.Files
+	result.Demo = flags.Demo
+
 	return result
 }
 
@@ -2,6 +2,7 @@ package config
 
 import (
 	"path/filepath"
+	"reflect"
 	"testing"
 )
 
@@ -14,6 +15,22 @@ func TestSaveAndLoad(t *testing.T) {
 		DefaultRegion:  "us-east-1",
 		LastRegion:     "us-west-2",
 		LastService:    "cloudwatch-logs",
+		TimeDisplay:    TimeDisplayZone,
+		TimeZone:       "America/New_York",
+		TimeFormat:     DefaultTimeFormat,
+
+		CorrelationFields: []string{"$.correlationId"},
+		RelatedGroups: map[string][]string{
+			"checkout": {"/aws/lambda/checkout", "/ecs/checkout-worker"},
+		},
+
+		SplitPercent: 30,
+		Zoom:         true,
+
+		Keys: map[string][]string{"tail": {"enter"}, "help": {}},
+
+		Theme:  "light",
+		Colors: map[string]string{"title": "#d75fd7"},
 	}
 
 	if err := Save(path, want); err != nil {
@@ -25,7 +42,7 @@ func TestSaveAndLoad(t *testing.T) {
 		t.Fatalf("load: %v", err)
 	}
 
-	if *got != *want {
+	if !reflect.DeepEqual(got, want) {
 		t.Fatalf("config mismatch: got %+v want %+v", got, want)
 	}
 }
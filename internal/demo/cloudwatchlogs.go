@@ -0,0 +1,519 @@
+// Package demo provides in-memory fakes of the AWS APIs sacha uses, so it
+// can run without credentials or network access.
+package demo
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/sachamama/sacha/internal/logs"
+)
+
+// Region is used when demo mode starts without a region.
+const Region = "us-east-1"
+
+const (
+	defaultLimit = 10000
+	// exportDuration is how long a fake export task takes to complete.
+	exportDuration = 20 * time.Second
+)
+
+var _ logs.CloudWatchLogsAPI = (*CloudWatchLogs)(nil)
+
+var (
+	fakesMu sync.Mutex
+	fakes   = map[string]*CloudWatchLogs{}
+)
+
+// For returns the fake of a profile and region, creating it on first use.
+// Each keeps its state, such as saved queries, for the whole process, and
+// generates its own events, as separate accounts would; an empty profile
+// stands for the default one.
+func For(profile, region string) *CloudWatchLogs {
+	fakesMu.Lock()
+	defer fakesMu.Unlock()
+	key := profile + "@" + region
+	if f, ok := fakes[key]; ok {
+		return f
+	}
+	f := newFake(profile, region, time.Now)
+	fakes[key] = f
+	return f
+}
+
+// CloudWatchLogs is an in-memory logs.CloudWatchLogsAPI. Events are
+// generated from the clock: asking for the same time range returns the same
+// events, and new ones keep appearing as time passes.
+type CloudWatchLogs struct {
+	region string
+	now    func() time.Time
+	groups []*group
+
+	mu      sync.Mutex
+	seq     int
+	queries map[string]types.QueryDefinition
+	running map[string]*cloudwatchlogs.StartQueryInput
+	exports []types.ExportTask
+	streams map[string]map[string]bool // created with CreateLogStream
+	written map[string][]logs.TailEvent
+}
+
+// New builds a fake for region using now as its clock.
+func New(region string, now func() time.Time) *CloudWatchLogs {
+	return newFake("", region, now)
+}
+
+func newFake(profile, region string, now func() time.Time) *CloudWatchLogs {
+	f := &CloudWatchLogs{
+		region:  region,
+		now:     now,
+		queries: map[string]types.QueryDefinition{},
+		running: map[string]*cloudwatchlogs.StartQueryInput{},
+		streams: map[string]map[string]bool{},
+		written: map[string][]logs.TailEvent{},
+	}
+	for _, g := range demoGroups {
+		g := g
+		if profile == "" {
+			g.seed = hash(region, g.name)
+		} else {
+			g.seed = hash(profile, region, g.name)
+		}
+		f.groups = append(f.groups, &g)
+	}
+	f.queries["demo-errors"] = types.QueryDefinition{
+		QueryDefinitionId: aws.String("demo-errors"),
+		Name:              aws.String("Recent errors"),
+		QueryString:       aws.String("fields @timestamp, @message | filter @message like /ERROR/ | sort @timestamp desc | limit 20"),
+		LogGroupNames:     []string{"/ecs/orders-api", "/ecs/inventory-worker"},
+		LastModified:      aws.Int64(now().UnixMilli()),
+	}
+	return f
+}
+
+func (f *CloudWatchLogs) group(name string) (*group, error) {
+	for _, g := range f.groups {
+		if g.name == name {
+			return g, nil
+		}
+	}
+	return nil, &types.ResourceNotFoundException{Message: aws.String("The specified log group does not exist: " + name)}
+}
+
+func (f *CloudWatchLogs) nextID(prefix string) string {
+	f.seq++
+	return fmt.Sprintf("%s-%06d", prefix, f.seq)
+}
+
+func (f *CloudWatchLogs) DescribeLogGroups(ctx context.Context, in *cloudwatchlogs.DescribeLogGroupsInput, _ ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.DescribeLogGroupsOutput, error) {
+	start, err := offset(in.NextToken)
+	if err != nil {
+		return nil, err
+	}
+	limit := int(aws.ToInt32(in.Limit))
+	if limit <= 0 {
+		limit = 50
+	}
+	out := &cloudwatchlogs.DescribeLogGroupsOutput{}
+	for i := start; i < len(f.groups); i++ {
+		if len(out.LogGroups) == limit {
+			out.NextToken = aws.String(strconv.Itoa(i))
+			break
+		}
+		g := f.groups[i]
+		if p := aws.ToString(in.LogGroupNamePrefix); p != "" && !strings.HasPrefix(g.name, p) {
+			continue
+		}
+		f.mu.Lock()
+		retention := g.retention
+		f.mu.Unlock()
+		out.LogGroups = append(out.LogGroups, types.LogGroup{
+			LogGroupName:    aws.String(g.name),
+			RetentionInDays: aws.Int32(retention),
+			StoredBytes:     aws.Int64(int64(g.seed%900+100) << 20),
+			Arn:             aws.String(fmt.Sprintf("arn:aws:logs:%s:123456789012:log-group:%s:*", f.region, g.name)),
+		})
+	}
+	return out, nil
+}
+
+// FilterLogEvents generates the events of one group between StartTime and
+// the earlier of EndTime and now. NextToken is an offset into that range.
+func (f *CloudWatchLogs) FilterLogEvents(ctx context.Context, in *cloudwatchlogs.FilterLogEventsInput, _ ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.FilterLogEventsOutput, error) {
+	g, err := f.group(aws.ToString(in.LogGroupName))
+	if err != nil {
+		return nil, err
+	}
+	skip, err := offset(in.NextToken)
+	if err != nil {
+		return nil, err
+	}
+	now := f.now()
+	start := now.Add(-time.Hour)
+	if in.StartTime != nil {
+		start = time.UnixMilli(*in.StartTime)
+	}
+	end := now
+	if in.EndTime != nil && time.UnixMilli(*in.EndTime).Before(now) {
+		end = time.UnixMilli(*in.EndTime)
+	}
+	limit := int(aws.ToInt32(in.Limit))
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+	// The fake filters the way local files do, on plain-text terms only.
+	match := logs.MatchPattern(aws.ToString(in.FilterPattern))
+
+	out := &cloudwatchlogs.FilterLogEventsOutput{}
+	matched := 0
+	for _, e := range f.events(g, start, end) {
+		if !match(e.Message) {
+			continue
+		}
+		matched++
+		if matched <= skip {
+			continue
+		}
+		if len(out.Events) == limit {
+			out.NextToken = aws.String(strconv.Itoa(matched - 1))
+			break
+		}
+		out.Events = append(out.Events, types.FilteredLogEvent{
+			EventId:       aws.String(e.ID),
+			LogStreamName: aws.String(e.LogStream),
+			Message:       aws.String(e.Message),
+			Timestamp:     aws.Int64(e.Timestamp.UnixMilli()),
+			IngestionTime: aws.Int64(e.IngestionTime.UnixMilli()),
+		})
+	}
+	return out, nil
+}
+
+func (f *CloudWatchLogs) DescribeQueryDefinitions(ctx context.Context, in *cloudwatchlogs.DescribeQueryDefinitionsInput, _ ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.DescribeQueryDefinitionsOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out := &cloudwatchlogs.DescribeQueryDefinitionsOutput{}
+	for _, d := range f.queries {
+		out.QueryDefinitions = append(out.QueryDefinitions, d)
+	}
+	return out, nil
+}
+
+func (f *CloudWatchLogs) PutQueryDefinition(ctx context.Context, in *cloudwatchlogs.PutQueryDefinitionInput, _ ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.PutQueryDefinitionOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	id := aws.ToString(in.QueryDefinitionId)
+	if id == "" {
+		id = f.nextID("query")
+	} else if _, ok := f.queries[id]; !ok {
+		return nil, &types.ResourceNotFoundException{Message: aws.String("query definition not found: " + id)}
+	}
+	f.queries[id] = types.QueryDefinition{
+		QueryDefinitionId: aws.String(id),
+		Name:              in.Name,
+		QueryString:       in.QueryString,
+		LogGroupNames:     append([]string(nil), in.LogGroupNames...),
+		LastModified:      aws.Int64(f.now().UnixMilli()),
+	}
+	return &cloudwatchlogs.PutQueryDefinitionOutput{QueryDefinitionId: aws.String(id)}, nil
+}
+
+func (f *CloudWatchLogs) DeleteQueryDefinition(ctx context.Context, in *cloudwatchlogs.DeleteQueryDefinitionInput, _ ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.DeleteQueryDefinitionOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	id := aws.ToString(in.QueryDefinitionId)
+	_, ok := f.queries[id]
+	delete(f.queries, id)
+	return &cloudwatchlogs.DeleteQueryDefinitionOutput{Success: ok}, nil
+}
+
+func (f *CloudWatchLogs) StartQuery(ctx context.Context, in *cloudwatchlogs.StartQueryInput, _ ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.StartQueryOutput, error) {
+	for _, name := range in.LogGroupNames {
+		if _, err := f.group(name); err != nil {
+			return nil, err
+		}
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	id := f.nextID("run")
+	f.running[id] = in
+	return &cloudwatchlogs.StartQueryOutput{QueryId: aws.String(id)}, nil
+}
+
+// GetQueryResults does not interpret the query: it returns the newest
+// events of the queried groups, limited to the query's "limit" if any.
+func (f *CloudWatchLogs) GetQueryResults(ctx context.Context, in *cloudwatchlogs.GetQueryResultsInput, _ ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.GetQueryResultsOutput, error) {
+	f.mu.Lock()
+	q, ok := f.running[aws.ToString(in.QueryId)]
+	f.mu.Unlock()
+	if !ok {
+		return nil, &types.ResourceNotFoundException{Message: aws.String("query not found")}
+	}
+	start := time.Unix(aws.ToInt64(q.StartTime), 0)
+	end := time.Unix(aws.ToInt64(q.EndTime), 0)
+	if now := f.now(); end.After(now) {
+		end = now
+	}
+	var batches [][]logs.TailEvent
+	for _, name := range q.LogGroupNames {
+		g, _ := f.group(name)
+		batches = append(batches, f.events(g, start, end))
+	}
+	events := logs.MergeEvents(batches...)
+	limit := queryLimit(aws.ToString(q.QueryString))
+	var scanned float64
+	out := &cloudwatchlogs.GetQueryResultsOutput{Status: types.QueryStatusComplete}
+	for i := len(events) - 1; i >= 0 && len(out.Results) < limit; i-- {
+		e := events[i]
+		out.Results = append(out.Results, []types.ResultField{
+			{Field: aws.String("@timestamp"), Value: aws.String(e.Timestamp.UTC().Format("2006-01-02 15:04:05.000"))},
+			{Field: aws.String("@logStream"), Value: aws.String(e.LogStream)},
+			{Field: aws.String("@message"), Value: aws.String(e.Message)},
+		})
+	}
+	for _, e := range events {
+		scanned += float64(len(e.Message))
+	}
+	out.Statistics = &types.QueryStatistics{RecordsMatched: float64(len(out.Results)), RecordsScanned: float64(len(events)), BytesScanned: scanned}
+	return out, nil
+}
+
+func (f *CloudWatchLogs) CreateExportTask(ctx context.Context, in *cloudwatchlogs.CreateExportTaskInput, _ ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.CreateExportTaskOutput, error) {
+	if _, err := f.group(aws.ToString(in.LogGroupName)); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	id := f.nextID("export")
+	f.exports = append(f.exports, types.ExportTask{
+		TaskId:            aws.String(id),
+		TaskName:          in.TaskName,
+		LogGroupName:      in.LogGroupName,
+		Destination:       in.Destination,
+		DestinationPrefix: in.DestinationPrefix,
+		From:              in.From,
+		To:                in.To,
+		Status:            &types.ExportTaskStatus{Code: types.ExportTaskStatusCodePending},
+		ExecutionInfo:     &types.ExportTaskExecutionInfo{CreationTime: aws.Int64(f.now().UnixMilli())},
+	})
+	return &cloudwatchlogs.CreateExportTaskOutput{TaskId: aws.String(id)}, nil
+}
+
+// DescribeExportTasks moves tasks from pending to running to completed as
+// time passes.
+func (f *CloudWatchLogs) DescribeExportTasks(ctx context.Context, in *cloudwatchlogs.DescribeExportTasksInput, _ ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.DescribeExportTasksOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	now := f.now()
+	out := &cloudwatchlogs.DescribeExportTasksOutput{}
+	for i := range f.exports {
+		t := &f.exports[i]
+		created := time.UnixMilli(aws.ToInt64(t.ExecutionInfo.CreationTime))
+		switch t.Status.Code {
+		case types.ExportTaskStatusCodePending, types.ExportTaskStatusCodeRunning:
+			switch age := now.Sub(created); {
+			case age >= exportDuration:
+				t.Status = &types.ExportTaskStatus{Code: types.ExportTaskStatusCodeCompleted, Message: aws.String("Completed successfully")}
+				t.ExecutionInfo.CompletionTime = aws.Int64(created.Add(exportDuration).UnixMilli())
+			case age >= exportDuration/4:
+				t.Status = &types.ExportTaskStatus{Code: types.ExportTaskStatusCodeRunning}
+			}
+		case types.ExportTaskStatusCodePendingCancel:
+			t.Status = &types.ExportTaskStatus{Code: types.ExportTaskStatusCodeCancelled, Message: aws.String("Cancelled by user")}
+		}
+		if id := aws.ToString(in.TaskId); id != "" && id != aws.ToString(t.TaskId) {
+			continue
+		}
+		if in.StatusCode != "" && in.StatusCode != t.Status.Code {
+			continue
+		}
+		out.ExportTasks = append(out.ExportTasks, *t)
+	}
+	return out, nil
+}
+
+func (f *CloudWatchLogs) CancelExportTask(ctx context.Context, in *cloudwatchlogs.CancelExportTaskInput, _ ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.CancelExportTaskOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i := range f.exports {
+		t := &f.exports[i]
+		if aws.ToString(t.TaskId) != aws.ToString(in.TaskId) {
+			continue
+		}
+		switch t.Status.Code {
+		case types.ExportTaskStatusCodePending, types.ExportTaskStatusCodeRunning:
+			t.Status = &types.ExportTaskStatus{Code: types.ExportTaskStatusCodePendingCancel}
+			return &cloudwatchlogs.CancelExportTaskOutput{}, nil
+		}
+		return nil, &types.InvalidOperationException{Message: aws.String("export task is not active")}
+	}
+	return nil, &types.ResourceNotFoundException{Message: aws.String("export task not found")}
+}
+
+// events merges the generated events of a group with those written to it.
+func (f *CloudWatchLogs) events(g *group, start, end time.Time) []logs.TailEvent {
+	f.mu.Lock()
+	var written []logs.TailEvent
+	for _, e := range f.written[g.name] {
+		if !e.Timestamp.Before(start) && !e.Timestamp.After(end) {
+			written = append(written, e)
+		}
+	}
+	f.mu.Unlock()
+	return logs.MergeEvents(g.events(start, end), written)
+}
+
+// DescribeLogStreams lists the streams written in the last hour and those
+// created through the API, most recent first.
+func (f *CloudWatchLogs) DescribeLogStreams(ctx context.Context, in *cloudwatchlogs.DescribeLogStreamsInput, _ ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.DescribeLogStreamsOutput, error) {
+	g, err := f.group(aws.ToString(in.LogGroupName))
+	if err != nil {
+		return nil, err
+	}
+	now := f.now()
+	last := map[string]int64{}
+	for _, e := range f.events(g, now.Add(-time.Hour), now) {
+		last[e.LogStream] = e.Timestamp.UnixMilli()
+	}
+	f.mu.Lock()
+	for name := range f.streams[g.name] {
+		if _, ok := last[name]; !ok {
+			last[name] = 0
+		}
+	}
+	f.mu.Unlock()
+
+	out := &cloudwatchlogs.DescribeLogStreamsOutput{}
+	for name, ts := range last {
+		if p := aws.ToString(in.LogStreamNamePrefix); p != "" && !strings.HasPrefix(name, p) {
+			continue
+		}
+		stream := types.LogStream{LogStreamName: aws.String(name)}
+		if ts > 0 {
+			stream.LastEventTimestamp = aws.Int64(ts)
+			stream.LastIngestionTime = aws.Int64(ts)
+		}
+		out.LogStreams = append(out.LogStreams, stream)
+	}
+	sort.Slice(out.LogStreams, func(i, j int) bool {
+		a, b := aws.ToInt64(out.LogStreams[i].LastEventTimestamp), aws.ToInt64(out.LogStreams[j].LastEventTimestamp)
+		if a != b {
+			return a > b
+		}
+		return aws.ToString(out.LogStreams[i].LogStreamName) < aws.ToString(out.LogStreams[j].LogStreamName)
+	})
+	if limit := int(aws.ToInt32(in.Limit)); limit > 0 && len(out.LogStreams) > limit {
+		out.LogStreams = out.LogStreams[:limit]
+	}
+	return out, nil
+}
+
+func (f *CloudWatchLogs) CreateLogStream(ctx context.Context, in *cloudwatchlogs.CreateLogStreamInput, _ ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.CreateLogStreamOutput, error) {
+	g, err := f.group(aws.ToString(in.LogGroupName))
+	if err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	name := aws.ToString(in.LogStreamName)
+	if f.streams[g.name][name] {
+		return nil, &types.ResourceAlreadyExistsException{Message: aws.String("The specified log stream already exists")}
+	}
+	if f.streams[g.name] == nil {
+		f.streams[g.name] = map[string]bool{}
+	}
+	f.streams[g.name][name] = true
+	return &cloudwatchlogs.CreateLogStreamOutput{}, nil
+}
+
+// PutLogEvents accepts events for streams created with CreateLogStream.
+func (f *CloudWatchLogs) PutLogEvents(ctx context.Context, in *cloudwatchlogs.PutLogEventsInput, _ ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.PutLogEventsOutput, error) {
+	g, err := f.group(aws.ToString(in.LogGroupName))
+	if err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	stream := aws.ToString(in.LogStreamName)
+	if !f.streams[g.name][stream] {
+		return nil, &types.ResourceNotFoundException{Message: aws.String("The specified log stream does not exist.")}
+	}
+	now := f.now()
+	for _, e := range in.LogEvents {
+		f.written[g.name] = append(f.written[g.name], logs.TailEvent{
+			ID:            f.nextID("put"),
+			Timestamp:     time.UnixMilli(aws.ToInt64(e.Timestamp)),
+			LogGroup:      g.name,
+			LogStream:     stream,
+			Message:       aws.ToString(e.Message),
+			IngestionTime: now,
+		})
+	}
+	f.written[g.name] = logs.MergeEvents(f.written[g.name])
+	return &cloudwatchlogs.PutLogEventsOutput{}, nil
+}
+
+// PutRetentionPolicy changes the retention reported by DescribeLogGroups.
+// Like the real API it only takes the supported periods. Generated events
+// are not expired.
+func (f *CloudWatchLogs) PutRetentionPolicy(ctx context.Context, in *cloudwatchlogs.PutRetentionPolicyInput, _ ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.PutRetentionPolicyOutput, error) {
+	g, err := f.group(aws.ToString(in.LogGroupName))
+	if err != nil {
+		return nil, err
+	}
+	days := aws.ToInt32(in.RetentionInDays)
+	if !slices.Contains(logs.RetentionDays, days) {
+		return nil, &types.InvalidParameterException{Message: aws.String(fmt.Sprintf("invalid retentionInDays: %d", days))}
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	g.retention = days
+	return &cloudwatchlogs.PutRetentionPolicyOutput{}, nil
+}
+
+func offset(token *string) (int, error) {
+	if aws.ToString(token) == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(*token)
+	if err != nil || n < 0 {
+		return 0, &types.InvalidParameterException{Message: aws.String("invalid next token")}
+	}
+	return n, nil
+}
+
+func queryLimit(query string) int {
+	const fallback = 100
+	idx := strings.LastIndex(query, "limit ")
+	if idx < 0 {
+		return fallback
+	}
+	fields := strings.Fields(query[idx+len("limit "):])
+	if len(fields) == 0 {
+		return fallback
+	}
+	n, err := strconv.Atoi(fields[0])
+	if err != nil || n <= 0 {
+		return fallback
+	}
+	return n
+}
+
+func hash(parts ...string) uint64 {
+	h := fnv.New64a()
+	for _, p := range parts {
+		h.Write([]byte(p))
+		h.Write([]byte{0})
+	}
+	return h.Sum64()
+}
@@ -0,0 +1,231 @@
+package demo
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/sachamama/sacha/internal/logs"
+)
+
+func fixedClock(t time.Time) func() time.Time {
+	return func() time.Time { return t }
+}
+
+func TestDescribeLogGroupsPaginates(t *testing.T) {
+	f := New("eu-west-1", fixedClock(time.Now()))
+	var names []string
+	var token *string
+	for {
+		out, err := f.DescribeLogGroups(context.Background(), &cloudwatchlogs.DescribeLogGroupsInput{Limit: aws.Int32(3), NextToken: token})
+		if err != nil {
+			t.Fatalf("describe: %v", err)
+		}
+		for _, g := range out.LogGroups {
+			names = append(names, aws.ToString(g.LogGroupName))
+		}
+		if out.NextToken == nil {
+			break
+		}
+		token = out.NextToken
+	}
+	if len(names) != len(demoGroups) {
+		t.Fatalf("expected %d groups, got %v", len(demoGroups), names)
+	}
+}
+
+func TestForKeepsProfilesApart(t *testing.T) {
+	if For("", "eu-west-1") != For("", "eu-west-1") {
+		t.Fatalf("a profile and region must keep one fake")
+	}
+	def, dev := For("", "eu-west-1"), For("demo-dev", "eu-west-1")
+	if def == dev {
+		t.Fatalf("profiles must not share a fake")
+	}
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	in := &cloudwatchlogs.FilterLogEventsInput{
+		LogGroupName: aws.String("/ecs/inventory-worker"),
+		StartTime:    aws.Int64(now.Add(-5 * time.Minute).UnixMilli()),
+		EndTime:      aws.Int64(now.UnixMilli()),
+	}
+	a, _ := def.FilterLogEvents(context.Background(), in)
+	b, _ := dev.FilterLogEvents(context.Background(), in)
+	if len(a.Events) == 0 || reflect.DeepEqual(a.Events, b.Events) {
+		t.Fatalf("profiles must see their own events")
+	}
+}
+
+func TestFilterLogEventsIsStableAndPaginates(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	f := New("eu-west-1", fixedClock(now))
+	in := func(limit int32, token *string) *cloudwatchlogs.FilterLogEventsInput {
+		return &cloudwatchlogs.FilterLogEventsInput{
+			LogGroupName: aws.String("/ecs/inventory-worker"),
+			StartTime:    aws.Int64(now.Add(-5 * time.Minute).UnixMilli()),
+			Limit:        aws.Int32(limit),
+			NextToken:    token,
+		}
+	}
+	all, err := f.FilterLogEvents(context.Background(), in(0, nil))
+	if err != nil {
+		t.Fatalf("filter: %v", err)
+	}
+	again, _ := f.FilterLogEvents(context.Background(), in(0, nil))
+	if len(all.Events) == 0 || !reflect.DeepEqual(all.Events, again.Events) {
+		t.Fatalf("events must be generated deterministically")
+	}
+
+	var paged []string
+	var token *string
+	for {
+		out, err := f.FilterLogEvents(context.Background(), in(25, token))
+		if err != nil {
+			t.Fatalf("filter page: %v", err)
+		}
+		for _, e := range out.Events {
+			paged = append(paged, aws.ToString(e.EventId))
+		}
+		if out.NextToken == nil {
+			break
+		}
+		token = out.NextToken
+	}
+	if len(paged) != len(all.Events) {
+		t.Fatalf("paging returned %d events, want %d", len(paged), len(all.Events))
+	}
+	for _, e := range all.Events {
+		if ts := time.UnixMilli(aws.ToInt64(e.Timestamp)); ts.After(now) || ts.Before(now.Add(-5*time.Minute)) {
+			t.Fatalf("event outside the range at %s", ts)
+		}
+	}
+}
+
+func TestStreamFeedsClientFeatures(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	client := logs.NewClientFromAPI(New("eu-west-1", fixedClock(now)))
+	ctx := context.Background()
+
+	events, _, err := client.FetchEvents(ctx, []string{"/aws/lambda/checkout"}, now.Add(-2*time.Minute))
+	if err != nil {
+		t.Fatalf("fetch: %v", err)
+	}
+	invocations := logs.ParseInvocations(events)
+	if len(invocations) == 0 {
+		t.Fatalf("expected Lambda invocations in %d events", len(events))
+	}
+
+	var id logs.CorrelationID
+	for _, e := range events {
+		for _, found := range logs.FindCorrelationIDs(e.Message, []logs.FieldPath{mustPath(t, "$.correlationId")}) {
+			if found.Kind == logs.CorrelationField {
+				id = found
+			}
+		}
+	}
+	if id.Value == "" {
+		t.Fatalf("expected a correlation ID in the checkout logs")
+	}
+	related, _, err := client.SearchEvents(ctx, []string{"/aws/lambda/checkout", "/ecs/orders-api"}, id.FilterPattern(), now.Add(-time.Hour), now)
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	groups := map[string]bool{}
+	for _, e := range related {
+		groups[e.LogGroup] = true
+	}
+	if len(groups) != 2 {
+		t.Fatalf("correlation ID should appear in both groups, got %v", groups)
+	}
+}
+
+func TestExportTasksProgress(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	f := New("eu-west-1", func() time.Time { return now })
+	client := logs.NewClientFromAPI(f)
+	ctx := context.Background()
+
+	id, err := client.CreateExportTask(ctx, logs.ExportRequest{LogGroup: "/ecs/orders-api", Bucket: "archive", From: now.Add(-time.Hour), To: now})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	status := func() string {
+		tasks, err := client.ListExportTasks(ctx)
+		if err != nil || len(tasks) != 1 || tasks[0].ID != id {
+			t.Fatalf("list: %v %+v", err, tasks)
+		}
+		return tasks[0].Status
+	}
+	if got := status(); got != "PENDING" {
+		t.Fatalf("new task status %s", got)
+	}
+	now = now.Add(exportDuration)
+	if got := status(); got != "COMPLETED" {
+		t.Fatalf("finished task status %s", got)
+	}
+	if err := client.CancelExportTask(ctx, id); err == nil {
+		t.Fatalf("completed task must not be cancellable")
+	}
+}
+
+func TestWrittenEventsAreTailed(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	client := logs.NewClientFromAPI(New("eu-west-1", fixedClock(now)))
+	ctx := context.Background()
+
+	if err := client.PutEvents(ctx, "/ecs/orders-api", "sacha-test", []string{"injected marker"}, now.Add(-time.Second)); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	found, _, err := client.SearchEvents(ctx, []string{"/ecs/orders-api"}, `"injected marker"`, now.Add(-time.Minute), now)
+	if err != nil || len(found) != 1 || found[0].LogStream != "sacha-test" {
+		t.Fatalf("written event not found: %v %+v", err, found)
+	}
+	streams, err := client.ListLogStreams(ctx, "/ecs/orders-api", 50)
+	if err != nil {
+		t.Fatalf("streams: %v", err)
+	}
+	names := map[string]bool{}
+	for _, s := range streams {
+		names[s.Name] = true
+	}
+	if !names["sacha-test"] {
+		t.Fatalf("created stream missing from %v", names)
+	}
+}
+
+func mustPath(t *testing.T, expr string) logs.FieldPath {
+	t.Helper()
+	p, err := logs.ParseFieldPath(expr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestPutRetentionPolicy(t *testing.T) {
+	f := New("eu-west-1", fixedClock(time.Now()))
+	client := logs.NewClientFromAPI(f)
+	if err := client.SetRetention(context.Background(), "/aws/lambda/checkout", 7); err != nil {
+		t.Fatalf("set retention: %v", err)
+	}
+	groups, _, err := client.ListLogGroups(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	for _, g := range groups {
+		if g.Name == "/aws/lambda/checkout" && g.RetentionDays != 7 {
+			t.Fatalf("retention = %d, want 7", g.RetentionDays)
+		}
+	}
+
+	if _, err := f.PutRetentionPolicy(context.Background(), &cloudwatchlogs.PutRetentionPolicyInput{
+		LogGroupName: aws.String("/aws/lambda/checkout"), RetentionInDays: aws.Int32(31),
+	}); err == nil {
+		t.Fatalf("expected unsupported periods to be rejected")
+	}
+	if err := client.SetRetention(context.Background(), "/aws/lambda/missing", 7); err == nil {
+		t.Fatalf("expected an error for an unknown group")
+	}
+}
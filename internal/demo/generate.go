@@ -0,0 +1,271 @@
+package demo
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"github.com/sachamama/sacha/internal/logs"
+)
+
+// line is one generated event, offset from the start of its slot.
+type line struct {
+	offset  time.Duration
+	stream  string
+	message string
+}
+
+// generator produces the lines of one slot. flow is seeded per slot and
+// shared by all groups, so related groups can log the same request IDs.
+type generator func(rng, flow *rand.Rand, at time.Time, slot int64) []line
+
+// group is a fake log group that logs once per period. Everything about a
+// slot derives from the group seed and the slot number.
+type group struct {
+	name      string
+	retention int32
+	period    time.Duration
+	gen       generator
+	seed      uint64
+}
+
+var demoGroups = []group{
+	{name: "/aws/lambda/checkout", retention: 14, period: 4 * time.Second, gen: lambdaGenerator("checkout", 512, true)},
+	{name: "/aws/lambda/payments", retention: 14, period: 7 * time.Second, gen: lambdaGenerator("payments", 256, false)},
+	{name: "/aws/lambda/nightly-report", retention: 30, period: 10 * time.Minute, gen: lambdaGenerator("nightly-report", 1024, false)},
+	{name: "/ecs/orders-api", retention: 30, period: 4 * time.Second, gen: ordersAPI},
+	{name: "/ecs/inventory-worker", retention: 30, period: 3 * time.Second, gen: inventoryWorker},
+	{name: "API-Gateway-Execution-Logs_a1b2c3d4e5/prod", retention: 7, period: 5 * time.Second, gen: apiGateway},
+	{name: "/aws/rds/cluster/demo-db/postgresql", retention: 7, period: 11 * time.Second, gen: postgres},
+}
+
+// events returns the events of every slot overlapping [start, end], in
+// timestamp order.
+func (g *group) events(start, end time.Time) []logs.TailEvent {
+	if end.Before(start) {
+		return nil
+	}
+	period := g.period.Milliseconds()
+	var out []logs.TailEvent
+	for slot := start.UnixMilli() / period; slot <= end.UnixMilli()/period; slot++ {
+		at := time.UnixMilli(slot * period)
+		rng := rand.New(rand.NewPCG(g.seed, uint64(slot)))
+		flow := rand.New(rand.NewPCG(uint64(slot), uint64(slot)))
+		for i, l := range g.gen(rng, flow, at, slot) {
+			ts := at.Add(min(l.offset, g.period-time.Millisecond))
+			lag := time.Duration(150+rng.IntN(1500)) * time.Millisecond
+			if ts.Before(start) || ts.After(end) {
+				continue
+			}
+			out = append(out, logs.TailEvent{
+				ID:            fmt.Sprintf("%019d%03d", slot, i),
+				Timestamp:     ts,
+				LogGroup:      g.name,
+				LogStream:     l.stream,
+				Message:       l.message,
+				IngestionTime: ts.Add(lag),
+			})
+		}
+	}
+	return logs.MergeEvents(out)
+}
+
+func uuid(rng *rand.Rand) string {
+	return fmt.Sprintf("%08x-%04x-%04x-%04x-%012x", rng.Uint32(), rng.Uint32()&0xffff, rng.Uint32()&0x0fff|0x4000, rng.Uint32()&0x3fff|0x8000, rng.Uint64()&0xffffffffffff)
+}
+
+func traceID(rng *rand.Rand, at time.Time) string {
+	return fmt.Sprintf("Root=1-%08x-%012x%012x", at.Unix(), rng.Uint64()&0xffffffffffff, rng.Uint64()&0xffffffffffff)
+}
+
+func orderID(rng *rand.Rand) string {
+	return fmt.Sprintf("ord-%05d", rng.IntN(100000))
+}
+
+func jsonLine(fields map[string]any) string {
+	data, _ := json.Marshal(fields)
+	return string(data)
+}
+
+// lambdaGenerator logs one invocation per slot. The checkout function
+// shares its correlation ID with the orders API request of the same slot.
+func lambdaGenerator(function string, memoryMB int, correlated bool) generator {
+	return func(rng, flow *rand.Rand, at time.Time, slot int64) []line {
+		stream := fmt.Sprintf("%s/[$LATEST]%016x", at.UTC().Format("2006/01/02"), uint64(slot/900)*0x9e3779b97f4a7c15)
+		requestID := uuid(rng)
+		correlationID := uuid(rng)
+		if correlated {
+			correlationID = uuid(flow)
+		}
+		var lines []line
+		add := func(offset time.Duration, msg string) {
+			lines = append(lines, line{offset: offset, stream: stream, message: msg})
+		}
+
+		cold := slot%15 == 0
+		offset := 20 * time.Millisecond
+		var initMS float64
+		if cold {
+			initMS = 180 + rng.Float64()*400
+			add(0, "INIT_START Runtime Version: nodejs:20.v22\tRuntime Version ARN: arn:aws:lambda:us-east-1::runtime:0b1f")
+			offset += time.Duration(initMS) * time.Millisecond
+		}
+		add(offset, fmt.Sprintf("START RequestId: %s Version: $LATEST", requestID))
+
+		timedOut := slot%47 == 0
+		durationMS := 30 + rng.ExpFloat64()*120
+		if timedOut {
+			durationMS = 3000
+		}
+		steps := []string{"received event", "validated order", "stored order"}
+		if function == "payments" {
+			steps = []string{"authorizing card", "authorization approved"}
+		}
+		for i, step := range steps {
+			level := "INFO"
+			if i == len(steps)-1 && rng.IntN(12) == 0 {
+				level, step = "ERROR", "downstream call failed: ThrottlingException: Rate exceeded"
+			}
+			add(offset+time.Duration(float64(i+1)/float64(len(steps)+1)*durationMS)*time.Millisecond, jsonLine(map[string]any{
+				"timestamp":     at.Add(offset).UTC().Format(time.RFC3339Nano),
+				"level":         level,
+				"requestId":     requestID,
+				"correlationId": correlationID,
+				"message":       step,
+				"orderId":       orderID(rng),
+			}))
+		}
+		end := offset + time.Duration(durationMS)*time.Millisecond
+		if timedOut {
+			add(end, fmt.Sprintf("%s %s Task timed out after 3.00 seconds", at.Add(end).UTC().Format(time.RFC3339Nano), requestID))
+		}
+		add(end+time.Millisecond, fmt.Sprintf("END RequestId: %s", requestID))
+		report := fmt.Sprintf("REPORT RequestId: %s\tDuration: %.2f ms\tBilled Duration: %d ms\tMemory Size: %d MB\tMax Memory Used: %d MB\t",
+			requestID, durationMS, int(durationMS)+1, memoryMB, memoryMB/4+rng.IntN(memoryMB/2))
+		if cold {
+			report += fmt.Sprintf("Init Duration: %.2f ms\t", initMS)
+		}
+		if timedOut {
+			report += "Status: timeout"
+		}
+		add(end+2*time.Millisecond, report)
+		return lines
+	}
+}
+
+var apiPaths = []string{"/orders", "/orders/{id}", "/cart", "/health"}
+
+// ordersAPI logs the request that triggered checkout plus some unrelated
+// traffic from two tasks.
+func ordersAPI(rng, flow *rand.Rand, at time.Time, slot int64) []line {
+	tasks := []string{"orders-api/app/3f9c2a71d0c54b8e", "orders-api/app/b27e8d14a6f94c03"}
+	var lines []line
+	requests := 1 + rng.IntN(4)
+	for i := 0; i < requests; i++ {
+		correlationID := uuid(rng)
+		path := apiPaths[rng.IntN(len(apiPaths))]
+		status := 200
+		if i == 0 {
+			correlationID = uuid(flow)
+			path = "/orders"
+		}
+		switch n := rng.IntN(20); {
+		case n == 0:
+			status = 500
+		case n < 3:
+			status = 404
+		case path == "/orders" && n < 8:
+			status = 201
+		}
+		level := "info"
+		if status >= 500 {
+			level = "error"
+		}
+		lines = append(lines, line{
+			offset: time.Duration(rng.IntN(3900)) * time.Millisecond,
+			stream: tasks[rng.IntN(len(tasks))],
+			message: jsonLine(map[string]any{
+				"level":         level,
+				"msg":           "request completed",
+				"method":        []string{"GET", "POST"}[rng.IntN(2)],
+				"path":          path,
+				"statusCode":    status,
+				"latencyMs":     5 + rng.IntN(400),
+				"correlationId": correlationID,
+				"traceId":       traceID(rng, at),
+				"user":          map[string]any{"id": fmt.Sprintf("u-%03d", rng.IntN(40))},
+			}),
+		})
+	}
+	if slot%20 == 0 {
+		lines = append(lines, line{offset: 0, stream: tasks[0], message: jsonLine(map[string]any{
+			"level": "warn", "msg": "slow query", "latencyMs": 1200 + rng.IntN(800), "correlationId": uuid(flow),
+		})})
+	}
+	return lines
+}
+
+// inventoryWorker writes plain-text lines, occasional Java stack traces
+// split over several events, and bursts of repeated warnings.
+func inventoryWorker(rng, flow *rand.Rand, at time.Time, slot int64) []line {
+	stream := fmt.Sprintf("inventory-worker/worker/%d", rng.IntN(2)+1)
+	stamp := func(d time.Duration) string { return at.Add(d).UTC().Format("2006-01-02 15:04:05.000") }
+	var lines []line
+	add := func(offset time.Duration, level, msg string) {
+		lines = append(lines, line{offset: offset, stream: stream, message: fmt.Sprintf("%s %-5s [worker-%d] %s", stamp(offset), level, rng.IntN(4)+1, msg)})
+	}
+	add(0, "INFO", fmt.Sprintf("reserved %d items for %s", 1+rng.IntN(6), orderID(rng)))
+	switch {
+	case slot%17 == 0:
+		offset := 900 * time.Millisecond
+		add(offset, "ERROR", fmt.Sprintf("failed to reserve stock for %s", orderID(rng)))
+		for _, l := range []string{
+			"java.lang.IllegalStateException: insufficient stock for sku-" + fmt.Sprint(rng.IntN(900)+100),
+			"\tat com.demo.inventory.Reservations.reserve(Reservations.java:88)",
+			"\tat com.demo.inventory.Worker.handle(Worker.java:41)",
+			"\tat java.base/java.lang.Thread.run(Thread.java:1583)",
+			"Caused by: java.sql.SQLTransientConnectionException: pool exhausted",
+			"\t... 3 more",
+		} {
+			lines = append(lines, line{offset: offset, stream: stream, message: l})
+		}
+	case slot%9 == 0:
+		for i := 0; i < 3+rng.IntN(4); i++ {
+			add(time.Duration(1200+i*150)*time.Millisecond, "WARN", "connection pool exhausted, retrying in 100ms")
+		}
+	}
+	if rng.IntN(3) == 0 {
+		add(2500*time.Millisecond, "DEBUG", fmt.Sprintf("queue depth %d", rng.IntN(50)))
+	}
+	return lines
+}
+
+func apiGateway(rng, flow *rand.Rand, at time.Time, slot int64) []line {
+	id := uuid(rng)
+	stream := fmt.Sprintf("%x", hash(at.Format("2006-01-02-15")))
+	status := 200
+	if rng.IntN(15) == 0 {
+		status = 502
+	}
+	return []line{
+		{offset: 0, stream: stream, message: fmt.Sprintf("(%s) Extended Request Id: %s=", id, strings.ToUpper(fmt.Sprintf("%x", rng.Uint64()))[:14])},
+		{offset: time.Millisecond, stream: stream, message: fmt.Sprintf("(%s) Verifying Usage Plan for request: %s. API Key:  API Stage: a1b2c3d4e5/prod", id, id)},
+		{offset: 2 * time.Millisecond, stream: stream, message: fmt.Sprintf("(%s) Method request body before transformations: {\"orderId\":\"%s\"}", id, orderID(rng))},
+		{offset: time.Duration(30+rng.IntN(300)) * time.Millisecond, stream: stream, message: fmt.Sprintf("(%s) Method completed with status: %d", id, status)},
+	}
+}
+
+func postgres(rng, flow *rand.Rand, at time.Time, slot int64) []line {
+	stamp := at.UTC().Format("2006-01-02 15:04:05 UTC")
+	pid := 4000 + rng.IntN(500)
+	msg := fmt.Sprintf("LOG:  checkpoint complete: wrote %d buffers (%.1f%%)", rng.IntN(300), rng.Float64()*3)
+	switch rng.IntN(6) {
+	case 0:
+		msg = fmt.Sprintf("LOG:  duration: %.3f ms  statement: SELECT * FROM orders WHERE id = '%s'", 500+rng.Float64()*2000, orderID(rng))
+	case 1:
+		msg = "ERROR:  deadlock detected"
+	}
+	return []line{{stream: "demo-db-instance-1", message: fmt.Sprintf("%s::@:[%d]:%s", stamp, pid, msg)}}
+}
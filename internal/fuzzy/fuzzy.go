@@ -0,0 +1,68 @@
+// Package fuzzy implements the case-insensitive subsequence matching used by
+// sacha's pickers and overlays.
+package fuzzy
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Match reports whether every rune of pattern appears in text in order,
+// ignoring case. The score rewards consecutive runs, matches at word starts
+// and early matches; higher is better.
+func Match(pattern, text string) (int, bool) {
+	p := []rune(strings.ToLower(pattern))
+	if len(p) == 0 {
+		return 0, true
+	}
+	t := []rune(text)
+	score, pi, prev := 0, 0, -2
+	for i, r := range t {
+		if pi == len(p) {
+			break
+		}
+		if unicode.ToLower(r) != p[pi] {
+			continue
+		}
+		score++
+		if i == prev+1 {
+			score += 5
+		}
+		if i == 0 || isBoundary(t[i-1]) {
+			score += 3
+		}
+		if pi == 0 {
+			score -= min(i, 10)
+		}
+		prev = i
+		pi++
+	}
+	if pi < len(p) {
+		return 0, false
+	}
+	return score, true
+}
+
+func isBoundary(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
+
+// Result is a matching item and its score.
+type Result struct {
+	Index int
+	Score int
+}
+
+// Rank returns the indexes of the items matching pattern, best first. Ties
+// keep the input order, so an empty pattern returns every item unchanged.
+func Rank(pattern string, items []string) []Result {
+	out := make([]Result, 0, len(items))
+	for i, item := range items {
+		if score, ok := Match(pattern, item); ok {
+			out = append(out, Result{Index: i, Score: score})
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Score > out[j].Score })
+	return out
+}
@@ -0,0 +1,38 @@
+package fuzzy
+
+import "testing"
+
+func TestMatch(t *testing.T) {
+	if _, ok := Match("tl", "tail selected"); !ok {
+		t.Fatalf("expected subsequence match")
+	}
+	if _, ok := Match("xyz", "tail selected"); ok {
+		t.Fatalf("unexpected match")
+	}
+	if _, ok := Match("", "anything"); !ok {
+		t.Fatalf("empty pattern must match")
+	}
+	if _, ok := Match("TAIL", "tail"); !ok {
+		t.Fatalf("matching must ignore case")
+	}
+}
+
+func TestRankPrefersWordStarts(t *testing.T) {
+	items := []string{"detail view", "tail selected", "export buffer"}
+
+	got := Rank("tail", items)
+
+	if len(got) != 2 {
+		t.Fatalf("expected two matches, got %+v", got)
+	}
+	if items[got[0].Index] != "tail selected" {
+		t.Fatalf("word-start match should rank first, got %q", items[got[0].Index])
+	}
+
+	all := Rank("", items)
+	for i, r := range all {
+		if r.Index != i {
+			t.Fatalf("empty pattern must keep input order, got %+v", all)
+		}
+	}
+}
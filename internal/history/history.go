@@ -0,0 +1,212 @@
+// Package history persists the searches, filters and queries run in sacha.
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sachamama/sacha/internal/fuzzy"
+)
+
+// Entry kinds.
+const (
+	KindGroupSearch = "search"
+	KindTailFilter  = "filter"
+	KindQuery       = "query"
+)
+
+// maxEntries caps the stored history; favorites are never evicted.
+const maxEntries = 500
+
+// Entry is one remembered input together with the context it ran in.
+type Entry struct {
+	Kind     string    `json:"kind"`
+	Text     string    `json:"text"`
+	Profile  string    `json:"profile,omitempty"`
+	Region   string    `json:"region,omitempty"`
+	Groups   []string  `json:"groups,omitempty"`
+	LastUsed time.Time `json:"lastUsed"`
+	Uses     int       `json:"uses"`
+	Favorite bool      `json:"favorite,omitempty"`
+}
+
+// key identifies an entry; groups are order-insensitive.
+func (e Entry) key() string {
+	groups := append([]string(nil), e.Groups...)
+	sort.Strings(groups)
+	return strings.Join([]string{e.Kind, e.Text, e.Profile, e.Region, strings.Join(groups, ",")}, "\x00")
+}
+
+// Store is a history file loaded in memory. It is safe for concurrent use.
+type Store struct {
+	path string
+
+	mu      sync.Mutex
+	entries []Entry // most recent first
+
+	// saveMu serializes saves, which run from concurrent commands, so an
+	// older snapshot never replaces a newer one.
+	saveMu sync.Mutex
+}
+
+// New returns an empty store that saves to path.
+func New(path string) *Store {
+	return &Store{path: path}
+}
+
+// Load reads the history file at path; a missing file yields an empty store.
+func Load(path string) (*Store, error) {
+	s := New(path)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read history: %w", err)
+	}
+	if err := json.Unmarshal(data, &s.entries); err != nil {
+		return nil, fmt.Errorf("parse history: %w", err)
+	}
+	sort.SliceStable(s.entries, func(i, j int) bool { return s.entries[i].LastUsed.After(s.entries[j].LastUsed) })
+	return s, nil
+}
+
+// Add records a use of e, merging it with an identical earlier entry.
+func (s *Store) Add(e Entry) {
+	e.Text = strings.TrimSpace(e.Text)
+	if e.Text == "" {
+		return
+	}
+	if e.LastUsed.IsZero() {
+		e.LastUsed = time.Now()
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e.Uses = 1
+	for i, old := range s.entries {
+		if old.key() == e.key() {
+			e.Uses = old.Uses + 1
+			e.Favorite = old.Favorite
+			s.entries = append(s.entries[:i], s.entries[i+1:]...)
+			break
+		}
+	}
+	s.entries = append([]Entry{e}, s.entries...)
+	s.evict()
+}
+
+// evict drops the oldest non-favorite entries beyond maxEntries.
+func (s *Store) evict() {
+	overflow := len(s.entries) - maxEntries
+	if overflow <= 0 {
+		return
+	}
+	drop := map[int]bool{}
+	for i := len(s.entries) - 1; i >= 0 && len(drop) < overflow; i-- {
+		if !s.entries[i].Favorite {
+			drop[i] = true
+		}
+	}
+	kept := make([]Entry, 0, len(s.entries)-len(drop))
+	for i, e := range s.entries {
+		if !drop[i] {
+			kept = append(kept, e)
+		}
+	}
+	s.entries = kept
+}
+
+// ToggleFavorite flips the favorite flag of the entry equal to e.
+func (s *Store) ToggleFavorite(e Entry) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.entries {
+		if s.entries[i].key() == e.key() {
+			s.entries[i].Favorite = !s.entries[i].Favorite
+			return s.entries[i].Favorite
+		}
+	}
+	return false
+}
+
+// Recall returns the distinct texts of one kind, most recent first. Entries
+// from the given profile and region come before the rest.
+func (s *Store) Recall(kind, profile, region string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var local, other []string
+	seen := map[string]bool{}
+	for _, e := range s.entries {
+		if e.Kind != kind || seen[e.Text] {
+			continue
+		}
+		seen[e.Text] = true
+		if e.Profile == profile && e.Region == region {
+			local = append(local, e.Text)
+		} else {
+			other = append(other, e.Text)
+		}
+	}
+	return append(local, other...)
+}
+
+// Search returns entries fuzzy-matching query against their text and log
+// groups. Favorites come first, then the best matches, then recency.
+func (s *Store) Search(query string) []Entry {
+	s.mu.Lock()
+	entries := append([]Entry(nil), s.entries...)
+	s.mu.Unlock()
+
+	haystack := make([]string, len(entries))
+	for i, e := range entries {
+		haystack[i] = e.Text + " " + strings.Join(e.Groups, " ")
+	}
+	ranked := fuzzy.Rank(query, haystack)
+	out := make([]Entry, 0, len(ranked))
+	for _, r := range ranked {
+		out = append(out, entries[r.Index])
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Favorite && !out[j].Favorite })
+	return out
+}
+
+// Save writes the history next to the config file, creating directories as
+// needed.
+func (s *Store) Save() error {
+	s.saveMu.Lock()
+	defer s.saveMu.Unlock()
+
+	s.mu.Lock()
+	data, err := json.MarshalIndent(s.entries, "", "  ")
+	s.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("marshal history: %w", err)
+	}
+	dir := filepath.Dir(s.path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("create history dir: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("write history: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write history: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write history: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("write history: %w", err)
+	}
+	return nil
+}
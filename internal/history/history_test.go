@@ -0,0 +1,107 @@
+package history
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAddMergesAndSaveRoundTrips(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	s, err := Load(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+
+	base := time.Unix(1700000000, 0)
+	s.Add(Entry{Kind: KindTailFilter, Text: "error", Profile: "dev", Region: "us-east-1", Groups: []string{"b", "a"}, LastUsed: base})
+	s.Add(Entry{Kind: KindGroupSearch, Text: "lambda", Profile: "dev", Region: "us-east-1", LastUsed: base.Add(time.Second)})
+	s.Add(Entry{Kind: KindTailFilter, Text: " error ", Profile: "dev", Region: "us-east-1", Groups: []string{"a", "b"}, LastUsed: base.Add(2 * time.Second)})
+	s.Add(Entry{Kind: KindTailFilter, Text: "   "})
+
+	if !s.ToggleFavorite(Entry{Kind: KindGroupSearch, Text: "lambda", Profile: "dev", Region: "us-east-1"}) {
+		t.Fatalf("expected favorite to be set")
+	}
+	if err := s.Save(); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	all := loaded.Search("")
+	if len(all) != 2 {
+		t.Fatalf("expected merged entries, got %+v", all)
+	}
+	if all[0].Text != "lambda" || !all[0].Favorite {
+		t.Fatalf("favorites must come first, got %+v", all[0])
+	}
+	if all[1].Text != "error" || all[1].Uses != 2 {
+		t.Fatalf("repeated entry must be merged, got %+v", all[1])
+	}
+}
+
+func TestRecallPrefersCurrentContext(t *testing.T) {
+	s, _ := Load(filepath.Join(t.TempDir(), "history.json"))
+	s.Add(Entry{Kind: KindTailFilter, Text: "here-old", Profile: "dev", Region: "us-east-1"})
+	s.Add(Entry{Kind: KindTailFilter, Text: "elsewhere", Profile: "prod", Region: "us-east-1"})
+	s.Add(Entry{Kind: KindTailFilter, Text: "here-new", Profile: "dev", Region: "us-east-1"})
+	s.Add(Entry{Kind: KindQuery, Text: "fields @message", Profile: "dev", Region: "us-east-1"})
+
+	got := s.Recall(KindTailFilter, "dev", "us-east-1")
+	want := []string{"here-new", "here-old", "elsewhere"}
+	if len(got) != len(want) {
+		t.Fatalf("got %v want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got %v want %v", got, want)
+		}
+	}
+}
+
+func TestSearchIsFuzzy(t *testing.T) {
+	s, _ := Load(filepath.Join(t.TempDir(), "history.json"))
+	s.Add(Entry{Kind: KindQuery, Text: "stats count(*) by bin(5m)", Groups: []string{"/aws/lambda/orders"}})
+	s.Add(Entry{Kind: KindTailFilter, Text: "timeout"})
+
+	got := s.Search("ordrs")
+	if len(got) != 1 || got[0].Kind != KindQuery {
+		t.Fatalf("expected fuzzy match on log group, got %+v", got)
+	}
+}
+
+func TestConcurrentSaves(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	s := New(path)
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.Add(Entry{Kind: KindQuery, Text: fmt.Sprintf("query %d", i)})
+			errs <- s.Save()
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("save: %v", err)
+		}
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if got := len(loaded.Search("")); got != 20 {
+		t.Fatalf("the last save must hold every entry, got %d", got)
+	}
+	if matches, _ := filepath.Glob(path + ".*"); len(matches) != 0 {
+		t.Fatalf("temporary files left behind: %v", matches)
+	}
+}
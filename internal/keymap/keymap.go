@@ -0,0 +1,173 @@
+// Package keymap holds sacha's key bindings: the defaults declared by the
+// app and its services, with overrides from the config file applied.
+package keymap
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Action is something a key can be bound to.
+type Action struct {
+	// Name identifies the action in the config file, e.g. "tail".
+	Name string
+	// Help says what the action does, e.g. "tail the selected log groups".
+	Help string
+	// Keys are the default keys, as bubbletea names them: "t", "ctrl+r",
+	// "shift+down", "space".
+	Keys []string
+}
+
+// Group is a set of actions listed together in the help overlay.
+type Group struct {
+	Title   string
+	Actions []Action
+}
+
+// Keymap is the effective set of bindings. All actions of a keymap are live
+// at the same time, so no key may be bound twice.
+type Keymap struct {
+	groups   []Group
+	bindings map[string]key.Binding
+}
+
+// New applies overrides, keyed by action name, to the default keys of the
+// groups. An empty override unbinds the action. Unknown actions and keys
+// bound to more than one action are reported together.
+func New(overrides map[string][]string, groups ...Group) (*Keymap, error) {
+	k := &Keymap{bindings: map[string]key.Binding{}}
+	var errs []error
+	known := map[string]bool{}
+	owner := map[string]string{}
+	for _, g := range groups {
+		eff := Group{Title: g.Title}
+		for _, a := range g.Actions {
+			known[a.Name] = true
+			if keys, ok := overrides[a.Name]; ok {
+				a.Keys = keys
+			}
+			a.Keys = normalize(a.Keys)
+			for _, name := range a.Keys {
+				if other, ok := owner[name]; ok {
+					errs = append(errs, fmt.Errorf("key %q is bound to both %s and %s", display(name), other, a.Name))
+					continue
+				}
+				owner[name] = a.Name
+			}
+			k.bindings[a.Name] = binding(a)
+			eff.Actions = append(eff.Actions, a)
+		}
+		k.groups = append(k.groups, eff)
+	}
+	for _, name := range slices.Sorted(maps.Keys(overrides)) {
+		if !known[name] {
+			errs = append(errs, fmt.Errorf("unknown action %q", name))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, fmt.Errorf("keys: %w", err)
+	}
+	return k, nil
+}
+
+// MustNew is New for defaults known not to conflict.
+func MustNew(overrides map[string][]string, groups ...Group) *Keymap {
+	k, err := New(overrides, groups...)
+	if err != nil {
+		panic(err)
+	}
+	return k
+}
+
+func binding(a Action) key.Binding {
+	if len(a.Keys) == 0 {
+		return key.NewBinding(key.WithDisabled())
+	}
+	names := make([]string, len(a.Keys))
+	for i, k := range a.Keys {
+		names[i] = display(k)
+	}
+	return key.NewBinding(key.WithKeys(a.Keys...), key.WithHelp(strings.Join(names, "/"), a.Help))
+}
+
+// normalize turns "space" into the " " bubbletea reports for the space bar.
+func normalize(keys []string) []string {
+	out := make([]string, len(keys))
+	for i, k := range keys {
+		if k == "space" {
+			k = " "
+		}
+		out[i] = k
+	}
+	return out
+}
+
+func display(k string) string {
+	if k == " " {
+		return "space"
+	}
+	return k
+}
+
+// Action returns the name of the action msg is bound to, or "".
+func (k *Keymap) Action(msg tea.KeyMsg) string {
+	for _, g := range k.groups {
+		for _, a := range g.Actions {
+			if key.Matches(msg, k.bindings[a.Name]) {
+				return a.Name
+			}
+		}
+	}
+	return ""
+}
+
+// Matches reports whether msg is bound to the action.
+func (k *Keymap) Matches(msg tea.KeyMsg, action string) bool {
+	return key.Matches(msg, k.bindings[action])
+}
+
+// Binding returns the binding of an action.
+func (k *Keymap) Binding(action string) key.Binding {
+	return k.bindings[action]
+}
+
+// Keys returns the keys of an action for display, e.g. "q/esc", or "" if
+// it is unbound.
+func (k *Keymap) Keys(action string) string {
+	b := k.bindings[action]
+	if !b.Enabled() {
+		return ""
+	}
+	return b.Help().Key
+}
+
+// Hint renders "keys label" for an action, or "" if it is unbound.
+func (k *Keymap) Hint(action, label string) string {
+	if keys := k.Keys(action); keys != "" {
+		return keys + " " + label
+	}
+	return ""
+}
+
+// Hints joins the hints of action and label pairs with commas, leaving out
+// unbound actions.
+func (k *Keymap) Hints(pairs ...string) string {
+	var hints []string
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if h := k.Hint(pairs[i], pairs[i+1]); h != "" {
+			hints = append(hints, h)
+		}
+	}
+	return strings.Join(hints, ", ")
+}
+
+// Groups returns the effective bindings, grouped for the help overlay.
+func (k *Keymap) Groups() []Group {
+	return k.groups
+}
@@ -0,0 +1,80 @@
+package keymap
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+var testGroups = []Group{
+	{Title: "App", Actions: []Action{
+		{Name: "quit", Help: "quit", Keys: []string{"q"}},
+		{Name: "help", Help: "toggle help", Keys: []string{"?"}},
+	}},
+	{Title: "Logs", Actions: []Action{
+		{Name: "tail", Help: "tail", Keys: []string{"t"}},
+		{Name: "select", Help: "select", Keys: []string{"space"}},
+		{Name: "down", Help: "move down", Keys: []string{"down", "j"}},
+	}},
+}
+
+func runes(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func TestDefaults(t *testing.T) {
+	k, err := New(nil, testGroups...)
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	if got := k.Action(runes("t")); got != "tail" {
+		t.Fatalf("t is bound to %q, want tail", got)
+	}
+	if got := k.Action(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")}); got != "select" {
+		t.Fatalf("space is bound to %q, want select", got)
+	}
+	if !k.Matches(tea.KeyMsg{Type: tea.KeyDown}, "down") {
+		t.Fatalf("down arrow should match down")
+	}
+	if got := k.Keys("down"); got != "down/j" {
+		t.Fatalf("keys of down = %q", got)
+	}
+	if got := k.Hints("select", "select", "tail", "tail"); got != "space select, t tail" {
+		t.Fatalf("hints = %q", got)
+	}
+}
+
+func TestOverrides(t *testing.T) {
+	k, err := New(map[string][]string{"tail": {"enter", "T"}, "help": {}}, testGroups...)
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	if k.Matches(runes("t"), "tail") {
+		t.Fatalf("the default key should be replaced")
+	}
+	if got := k.Action(tea.KeyMsg{Type: tea.KeyEnter}); got != "tail" {
+		t.Fatalf("enter is bound to %q, want tail", got)
+	}
+	if got := k.Action(runes("?")); got != "" {
+		t.Fatalf("unbound help still matches %q", got)
+	}
+	if got := k.Hint("help", "help"); got != "" {
+		t.Fatalf("unbound action has hint %q", got)
+	}
+	if got := k.Groups()[1].Actions[0].Keys; len(got) != 2 || got[0] != "enter" {
+		t.Fatalf("groups should show effective keys, got %v", got)
+	}
+}
+
+func TestConflictsAndUnknownActions(t *testing.T) {
+	_, err := New(map[string][]string{"tail": {"q"}, "follow": {"f"}}, testGroups...)
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	for _, want := range []string{`key "q" is bound to both quit and tail`, `unknown action "follow"`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("error %q does not mention %q", err, want)
+		}
+	}
+}
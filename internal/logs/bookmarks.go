@@ -0,0 +1,101 @@
+package logs
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultTimelineContext is how many events before and after a bookmark an
+// incident timeline shows.
+const DefaultTimelineContext = 3
+
+// Bookmark marks an event with an optional note. The event is copied, so a
+// bookmark outlives the tail buffer it was taken from.
+type Bookmark struct {
+	Event TailEvent
+	Note  string
+}
+
+// SameEvent reports whether a and b are the same CloudWatch event.
+func SameEvent(a, b TailEvent) bool {
+	return a.ID == b.ID && a.LogGroup == b.LogGroup && a.Region == b.Region && a.Profile == b.Profile
+}
+
+// SortBookmarks orders marks by event time, oldest first.
+func SortBookmarks(marks []Bookmark) {
+	sort.SliceStable(marks, func(i, j int) bool {
+		return marks[i].Event.Timestamp.Before(marks[j].Event.Timestamp)
+	})
+}
+
+// WriteTimeline writes marks as a Markdown incident timeline, oldest first.
+// Each mark is shown with up to n events before and after it from events;
+// marks that are no longer in events are shown on their own.
+func WriteTimeline(w io.Writer, marks []Bookmark, events []TailEvent, n int, now time.Time) error {
+	sorted := append([]Bookmark(nil), marks...)
+	SortBookmarks(sorted)
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "# Incident timeline")
+	fmt.Fprintln(bw)
+	fmt.Fprintf(bw, "Exported %s, %d bookmarks.\n", now.UTC().Format(time.RFC3339), len(sorted))
+	for _, mark := range sorted {
+		e := mark.Event
+		fmt.Fprintln(bw)
+		fmt.Fprintf(bw, "## %s %s\n", e.Timestamp.UTC().Format("2006-01-02 15:04:05.000Z"), timelineSource(e))
+		fmt.Fprintln(bw)
+		if note := strings.TrimSpace(mark.Note); note != "" {
+			for _, line := range strings.Split(note, "\n") {
+				fmt.Fprintf(bw, "> %s\n", line)
+			}
+			fmt.Fprintln(bw)
+		}
+		fmt.Fprintln(bw, "```")
+		for _, c := range surrounding(events, e, n) {
+			prefix := "  "
+			if SameEvent(c, e) {
+				prefix = "> "
+			}
+			fmt.Fprintf(bw, "%s%s %s %s\n", prefix, c.Timestamp.UTC().Format("15:04:05.000"), c.LogGroup, timelineMessage(c.Message))
+		}
+		fmt.Fprintln(bw, "```")
+	}
+	return bw.Flush()
+}
+
+// surrounding returns e with up to n events on each side of it, or just e
+// if events no longer holds it.
+func surrounding(events []TailEvent, e TailEvent, n int) []TailEvent {
+	for i, c := range events {
+		if SameEvent(c, e) {
+			return events[max(i-n, 0):min(i+n+1, len(events))]
+		}
+	}
+	return []TailEvent{e}
+}
+
+func timelineSource(e TailEvent) string {
+	label := e.LogGroup
+	if e.LogStream != "" {
+		label += " / " + e.LogStream
+	}
+	switch {
+	case e.Profile != "":
+		label += " (" + e.Profile + "@" + e.Region + ")"
+	case e.Region != "":
+		label += " (" + e.Region + ")"
+	}
+	return label
+}
+
+// timelineMessage keeps continuation lines inside the code block, indented
+// under the event they belong to.
+func timelineMessage(msg string) string {
+	msg = strings.TrimSpace(msg)
+	msg = strings.ReplaceAll(msg, "```", "'''")
+	return strings.ReplaceAll(msg, "\n", "\n    ")
+}
@@ -0,0 +1,55 @@
+package logs
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteTimeline(t *testing.T) {
+	base := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	var events []TailEvent
+	for i := 0; i < 10; i++ {
+		events = append(events, TailEvent{
+			ID:        fmt.Sprint(i),
+			LogGroup:  "/ecs/orders-api",
+			LogStream: "web/1",
+			Region:    "eu-west-1",
+			Timestamp: base.Add(time.Duration(i) * time.Second),
+			Message:   fmt.Sprintf("line %d", i),
+		})
+	}
+	gone := TailEvent{ID: "old", LogGroup: "/aws/lambda/checkout", Region: "eu-west-1", Timestamp: base.Add(-time.Hour), Message: "first error\n  at Handler.run"}
+	marks := []Bookmark{
+		{Event: events[5], Note: "payments start failing"},
+		{Event: gone},
+	}
+
+	var b strings.Builder
+	if err := WriteTimeline(&b, marks, events, 2, base); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+
+	if !strings.Contains(out, "2 bookmarks") {
+		t.Fatalf("missing summary:\n%s", out)
+	}
+	if strings.Index(out, "/aws/lambda/checkout") > strings.Index(out, "/ecs/orders-api / web/1 (eu-west-1)") {
+		t.Fatalf("marks must be ordered by event time:\n%s", out)
+	}
+	if !strings.Contains(out, "> payments start failing") {
+		t.Fatalf("missing note:\n%s", out)
+	}
+	for _, want := range []string{"  10:00:03.000 /ecs/orders-api line 3", "> 10:00:05.000 /ecs/orders-api line 5", "  10:00:07.000 /ecs/orders-api line 7"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "line 2") || strings.Contains(out, "line 8") {
+		t.Fatalf("context must be limited to 2 events:\n%s", out)
+	}
+	if !strings.Contains(out, "> 09:00:00.000 /aws/lambda/checkout first error\n      at Handler.run") {
+		t.Fatalf("mark outside the buffer must be written on its own:\n%s", out)
+	}
+}
@@ -7,12 +7,33 @@ import (
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+)
+
+const (
+	// maxSearchEvents caps how many events SearchEvents returns per group.
+	maxSearchEvents = 500
+	// maxSearchPages caps the pages SearchEvents reads per group. Selective
+	// patterns over large groups return many empty pages.
+	maxSearchPages = 20
 )
 
 // CloudWatchLogsAPI captures the AWS SDK methods we use.
 type CloudWatchLogsAPI interface {
 	DescribeLogGroups(ctx context.Context, params *cloudwatchlogs.DescribeLogGroupsInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.DescribeLogGroupsOutput, error)
 	FilterLogEvents(ctx context.Context, params *cloudwatchlogs.FilterLogEventsInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.FilterLogEventsOutput, error)
+	DescribeQueryDefinitions(ctx context.Context, params *cloudwatchlogs.DescribeQueryDefinitionsInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.DescribeQueryDefinitionsOutput, error)
+	PutQueryDefinition(ctx context.Context, params *cloudwatchlogs.PutQueryDefinitionInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.PutQueryDefinitionOutput, error)
+	DeleteQueryDefinition(ctx context.Context, params *cloudwatchlogs.DeleteQueryDefinitionInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.DeleteQueryDefinitionOutput, error)
+	StartQuery(ctx context.Context, params *cloudwatchlogs.StartQueryInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.StartQueryOutput, error)
+	GetQueryResults(ctx context.Context, params *cloudwatchlogs.GetQueryResultsInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.GetQueryResultsOutput, error)
+	CreateExportTask(ctx context.Context, params *cloudwatchlogs.CreateExportTaskInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.CreateExportTaskOutput, error)
+	DescribeExportTasks(ctx context.Context, params *cloudwatchlogs.DescribeExportTasksInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.DescribeExportTasksOutput, error)
+	CancelExportTask(ctx context.Context, params *cloudwatchlogs.CancelExportTaskInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.CancelExportTaskOutput, error)
+	DescribeLogStreams(ctx context.Context, params *cloudwatchlogs.DescribeLogStreamsInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.DescribeLogStreamsOutput, error)
+	CreateLogStream(ctx context.Context, params *cloudwatchlogs.CreateLogStreamInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.CreateLogStreamOutput, error)
+	PutLogEvents(ctx context.Context, params *cloudwatchlogs.PutLogEventsInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.PutLogEventsOutput, error)
+	PutRetentionPolicy(ctx context.Context, params *cloudwatchlogs.PutRetentionPolicyInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.PutRetentionPolicyOutput, error)
 }
 
 type Client struct {
@@ -25,17 +46,50 @@ func NewClient(cfg aws.Config) *Client {
 	}
 }
 
+// NewClientFromAPI wraps another implementation of the API, such as the
+// in-memory fake used in demo mode.
+func NewClientFromAPI(api CloudWatchLogsAPI) *Client {
+	return &Client{api: api}
+}
+
 type LogGroup struct {
 	Name          string
 	RetentionDays int32
 	StoredBytes   int64
+	// Region and Profile are set by callers that combine groups from
+	// several regions or accounts.
+	Region  string
+	Profile string
 }
 
 type TailEvent struct {
+	// ID is the CloudWatch event ID, unique within a log group.
+	ID        string
 	Timestamp time.Time
 	LogGroup  string
 	LogStream string
 	Message   string
+	// IngestionTime is when CloudWatch received the event; zero if unknown.
+	IngestionTime time.Time
+	// Region and Profile are set by callers that merge events from
+	// several regions or accounts.
+	Region  string
+	Profile string
+	// Parts holds the events JoinMultiline folded into this one, starting
+	// with the event itself; nil for events as read.
+	Parts []TailEvent
+	// Repeats holds the occurrences CollapseRepeats folded into this event,
+	// oldest first and starting with the event itself; nil otherwise.
+	Repeats []TailEvent
+}
+
+// Lag returns the delay between the event timestamp and its ingestion, or
+// zero when the ingestion time is unknown.
+func (e TailEvent) Lag() time.Duration {
+	if e.IngestionTime.IsZero() {
+		return 0
+	}
+	return e.IngestionTime.Sub(e.Timestamp)
 }
 
 // ListLogGroups returns a page of log groups and the next token, if any.
@@ -76,16 +130,11 @@ func (c *Client) FetchEvents(ctx context.Context, groups []string, start time.Ti
 		}
 
 		for _, e := range out.Events {
-			ts := time.Unix(0, aws.ToInt64(e.Timestamp)*int64(time.Millisecond))
-			if ts.After(nextStart) {
-				nextStart = ts
+			event := toTailEvent(group, e)
+			if event.Timestamp.After(nextStart) {
+				nextStart = event.Timestamp
 			}
-			events = append(events, TailEvent{
-				Timestamp: ts,
-				LogGroup:  group,
-				LogStream: aws.ToString(e.LogStreamName),
-				Message:   aws.ToString(e.Message),
-			})
+			events = append(events, event)
 		}
 	}
 
@@ -96,3 +145,56 @@ func (c *Client) FetchEvents(ctx context.Context, groups []string, start time.Ti
 	}
 	return events, nextStart, nil
 }
+
+// SearchEvents returns the events of the given groups matching a CloudWatch
+// filter pattern between start and end, ordered by timestamp. It reads at
+// most maxSearchEvents events and maxSearchPages pages per group, and
+// reports whether it stopped before the end of a group.
+func (c *Client) SearchEvents(ctx context.Context, groups []string, pattern string, start, end time.Time) ([]TailEvent, bool, error) {
+	events := make([]TailEvent, 0)
+	truncated := false
+	for _, group := range groups {
+		var token *string
+		for count, pages := 0, 0; ; {
+			if count >= maxSearchEvents || pages >= maxSearchPages {
+				truncated = true
+				break
+			}
+			out, err := c.api.FilterLogEvents(ctx, &cloudwatchlogs.FilterLogEventsInput{
+				LogGroupName:  aws.String(group),
+				FilterPattern: aws.String(pattern),
+				StartTime:     aws.Int64(start.UnixMilli()),
+				EndTime:       aws.Int64(end.UnixMilli()),
+				NextToken:     token,
+			})
+			if err != nil {
+				return nil, false, fmt.Errorf("search %s: %w", group, err)
+			}
+			pages++
+			for _, e := range out.Events {
+				events = append(events, toTailEvent(group, e))
+			}
+			count += len(out.Events)
+			if out.NextToken == nil || aws.ToString(out.NextToken) == "" {
+				break
+			}
+			token = out.NextToken
+		}
+	}
+	sortEvents(events)
+	return events, truncated, nil
+}
+
+func toTailEvent(group string, e types.FilteredLogEvent) TailEvent {
+	event := TailEvent{
+		ID:        aws.ToString(e.EventId),
+		Timestamp: time.UnixMilli(aws.ToInt64(e.Timestamp)),
+		LogGroup:  group,
+		LogStream: aws.ToString(e.LogStreamName),
+		Message:   aws.ToString(e.Message),
+	}
+	if e.IngestionTime != nil {
+		event.IngestionTime = time.UnixMilli(aws.ToInt64(e.IngestionTime))
+	}
+	return event
+}
@@ -0,0 +1,49 @@
+package logs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+)
+
+// fakeSearchAPI returns empty pages with a next token until pages run out,
+// as FilterLogEvents does for selective patterns over large groups.
+type fakeSearchAPI struct {
+	CloudWatchLogsAPI
+	pages int
+	calls int
+}
+
+func (f *fakeSearchAPI) FilterLogEvents(ctx context.Context, params *cloudwatchlogs.FilterLogEventsInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.FilterLogEventsOutput, error) {
+	f.calls++
+	out := &cloudwatchlogs.FilterLogEventsOutput{}
+	if f.calls < f.pages {
+		out.NextToken = aws.String("more")
+	}
+	return out, nil
+}
+
+func TestSearchEventsPageLimit(t *testing.T) {
+	end := time.Now()
+	api := &fakeSearchAPI{pages: 3}
+	client := &Client{api: api}
+	if _, truncated, err := client.SearchEvents(context.Background(), []string{"/a"}, `"id"`, end.Add(-time.Hour), end); err != nil || truncated {
+		t.Fatalf("short search: truncated=%v err=%v", truncated, err)
+	}
+	if api.calls != 3 {
+		t.Fatalf("read %d pages, want 3", api.calls)
+	}
+
+	api = &fakeSearchAPI{pages: 1000}
+	client = &Client{api: api}
+	_, truncated, err := client.SearchEvents(context.Background(), []string{"/a"}, `"id"`, end.Add(-time.Hour), end)
+	if err != nil || !truncated {
+		t.Fatalf("long search: truncated=%v err=%v", truncated, err)
+	}
+	if api.calls != maxSearchPages {
+		t.Fatalf("read %d pages, want %d", api.calls, maxSearchPages)
+	}
+}
@@ -0,0 +1,61 @@
+package logs
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Correlation identifier kinds.
+const (
+	CorrelationRequestID = "request-id"
+	CorrelationTraceID   = "trace-id"
+	CorrelationField     = "field"
+)
+
+// DefaultCorrelationFields are the JSON fields checked when none are configured.
+var DefaultCorrelationFields = []string{"$.correlationId", "$.requestId", "$.traceId"}
+
+// CorrelationID is an identifier that ties events of one request together.
+type CorrelationID struct {
+	Kind  string
+	Name  string // field path for CorrelationField, otherwise a fixed label
+	Value string
+}
+
+var (
+	// START/END/REPORT lines and the tab-separated runtime prefix.
+	lambdaRequestIDRe = regexp.MustCompile(`(?:RequestId:\s*|^\S+\t)([0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12})`)
+	xrayRootRe        = regexp.MustCompile(`Root=(1-[0-9a-fA-F]{8}-[0-9a-fA-F]{24})`)
+)
+
+// FindCorrelationIDs detects Lambda request IDs, X-Ray trace IDs and the
+// given JSON fields in a message. Duplicate values are reported once.
+func FindCorrelationIDs(message string, fields []FieldPath) []CorrelationID {
+	var out []CorrelationID
+	seen := map[string]bool{}
+	add := func(id CorrelationID) {
+		if id.Value == "" || seen[id.Value] {
+			return
+		}
+		seen[id.Value] = true
+		out = append(out, id)
+	}
+	if m := lambdaRequestIDRe.FindStringSubmatch(message); m != nil {
+		add(CorrelationID{Kind: CorrelationRequestID, Name: "RequestId", Value: strings.ToLower(m[1])})
+	}
+	if m := xrayRootRe.FindStringSubmatch(message); m != nil {
+		add(CorrelationID{Kind: CorrelationTraceID, Name: "X-Ray", Value: m[1]})
+	}
+	for _, f := range fields {
+		if v, ok := f.Lookup(message); ok && v != "null" {
+			add(CorrelationID{Kind: CorrelationField, Name: f.String(), Value: v})
+		}
+	}
+	return out
+}
+
+// FilterPattern returns a CloudWatch filter pattern matching the identifier
+// as an exact term.
+func (id CorrelationID) FilterPattern() string {
+	return `"` + strings.ReplaceAll(id.Value, `"`, `\"`) + `"`
+}
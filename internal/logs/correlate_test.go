@@ -0,0 +1,60 @@
+package logs
+
+import "testing"
+
+func TestFindCorrelationIDs(t *testing.T) {
+	fields := []FieldPath{}
+	for _, f := range DefaultCorrelationFields {
+		p, err := ParseFieldPath(f)
+		if err != nil {
+			t.Fatalf("parse %s: %v", f, err)
+		}
+		fields = append(fields, p)
+	}
+
+	cases := []struct {
+		name    string
+		message string
+		want    []CorrelationID
+	}{
+		{
+			name:    "lambda report",
+			message: "REPORT RequestId: 3F2504E0-4F89-11D3-9A0C-0305E82C3301\tDuration: 12.3 ms",
+			want:    []CorrelationID{{Kind: CorrelationRequestID, Name: "RequestId", Value: "3f2504e0-4f89-11d3-9a0c-0305e82c3301"}},
+		},
+		{
+			name:    "lambda runtime prefix with json",
+			message: "2024-01-01T00:00:00.000Z\t3f2504e0-4f89-11d3-9a0c-0305e82c3301\tINFO\t{\"correlationId\":\"order-42\"}",
+			want: []CorrelationID{
+				{Kind: CorrelationRequestID, Name: "RequestId", Value: "3f2504e0-4f89-11d3-9a0c-0305e82c3301"},
+				{Kind: CorrelationField, Name: "$.correlationId", Value: "order-42"},
+			},
+		},
+		{
+			name:    "xray header",
+			message: "forwarding X-Amzn-Trace-Id: Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8",
+			want:    []CorrelationID{{Kind: CorrelationTraceID, Name: "X-Ray", Value: "1-5759e988-bd862e3fe1be46a994272793"}},
+		},
+		{
+			name:    "duplicate values",
+			message: `{"requestId":"abc","traceId":"abc"}`,
+			want:    []CorrelationID{{Kind: CorrelationField, Name: "$.requestId", Value: "abc"}},
+		},
+		{
+			name:    "nothing",
+			message: "hello world",
+		},
+	}
+
+	for _, tc := range cases {
+		got := FindCorrelationIDs(tc.message, fields)
+		if len(got) != len(tc.want) {
+			t.Fatalf("%s: got %+v want %+v", tc.name, got, tc.want)
+		}
+		for i := range got {
+			if got[i] != tc.want[i] {
+				t.Fatalf("%s: id %d got %+v want %+v", tc.name, i, got[i], tc.want[i])
+			}
+		}
+	}
+}
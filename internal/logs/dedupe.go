@@ -0,0 +1,77 @@
+package logs
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	uuidRe   = regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`)
+	hexRe    = regexp.MustCompile(`\b(0x)?[0-9a-fA-F]{8,}\b`)
+	numberRe = regexp.MustCompile(`\d+`)
+)
+
+// NormalizeMessage reduces a message to its shape so that lines differing
+// only in timestamps, counters or IDs compare equal: UUIDs, hex strings and
+// numbers are replaced by placeholders and whitespace is collapsed. Numbers
+// that look like HTTP status codes are kept, so a burst of 500s does not
+// fold into the 200s before it.
+func NormalizeMessage(msg string) string {
+	msg = uuidRe.ReplaceAllString(msg, "<uuid>")
+	msg = hexRe.ReplaceAllString(msg, "<hex>")
+	var b strings.Builder
+	last := 0
+	for _, loc := range numberRe.FindAllStringIndex(msg, -1) {
+		if isStatusCode(msg, loc[0], loc[1]) {
+			continue
+		}
+		b.WriteString(msg[last:loc[0]])
+		b.WriteByte('#')
+		last = loc[1]
+	}
+	b.WriteString(msg[last:])
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// isStatusCode reports whether msg[start:end] is a number from 100 to 599
+// standing alone, as in "GET /orders 500" or "status=404", rather than part
+// of a timestamp, a duration such as "250ms" or an ID.
+func isStatusCode(msg string, start, end int) bool {
+	if end-start != 3 || msg[start] < '1' || msg[start] > '5' {
+		return false
+	}
+	if start > 0 && !strings.ContainsRune(" \t=:\"([", rune(msg[start-1])) {
+		return false
+	}
+	return end == len(msg) || strings.ContainsRune(" \t,;)]}\"", rune(msg[end]))
+}
+
+type groupKey struct {
+	profile, region, group string
+}
+
+// CollapseRepeats folds runs of events with the same normalized message in
+// the same log group into their first event, which keeps its position and
+// lists every occurrence in Repeats. Events of other groups in between do
+// not break a run. The input is not modified.
+func CollapseRepeats(events []TailEvent) []TailEvent {
+	out := make([]TailEvent, 0, len(events))
+	last := map[groupKey]int{} // index in out of each group's latest event
+	shapes := map[groupKey]string{}
+	for _, e := range events {
+		key := groupKey{e.Profile, e.Region, e.LogGroup}
+		shape := NormalizeMessage(e.Message)
+		if idx, ok := last[key]; ok && shapes[key] == shape {
+			run := &out[idx]
+			if run.Repeats == nil {
+				run.Repeats = []TailEvent{*run}
+			}
+			run.Repeats = append(run.Repeats, e)
+			continue
+		}
+		last[key] = len(out)
+		shapes[key] = shape
+		out = append(out, e)
+	}
+	return out
+}
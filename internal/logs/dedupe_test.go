@@ -0,0 +1,75 @@
+package logs
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNormalizeMessage(t *testing.T) {
+	a := NormalizeMessage("2024-05-01 10:00:00.150 WARN  [worker-2] pool exhausted, retrying in 100ms (req 1b4e28ba-2fa1-41d2-883f-0016d3cca427)")
+	b := NormalizeMessage("2024-05-01 10:00:01.300 WARN [worker-3]  pool exhausted, retrying in 250ms (req 6fa459ea-ee8a-4ca4-894e-db77e160355e)")
+	if a != b {
+		t.Fatalf("expected equal shapes:\n%s\n%s", a, b)
+	}
+	if NormalizeMessage("user 42 logged in") == NormalizeMessage("user 42 logged out") {
+		t.Fatalf("different words must not compare equal")
+	}
+	if got := NormalizeMessage("trace deadbeefcafe0042 done"); got != "trace <hex> done" {
+		t.Fatalf("unexpected hex normalization %q", got)
+	}
+}
+
+func TestNormalizeMessageKeepsStatusCodes(t *testing.T) {
+	for _, pair := range [][2]string{
+		{"GET /orders 200 12ms", "GET /orders 500 12ms"},
+		{`{"path":"/orders","statusCode":200}`, `{"path":"/orders","statusCode":503}`},
+		{"request done status=200", "request done status=404"},
+	} {
+		if NormalizeMessage(pair[0]) == NormalizeMessage(pair[1]) {
+			t.Fatalf("different status codes must not compare equal: %q, %q", pair[0], pair[1])
+		}
+	}
+	for _, pair := range [][2]string{
+		{"GET /orders 200 12ms", "GET /orders 200 340ms"},
+		{"10:00:00.150 took 150ms", "10:00:01.300 took 300ms"},
+		{"processed 1200 items", "processed 1500 items"},
+	} {
+		if NormalizeMessage(pair[0]) != NormalizeMessage(pair[1]) {
+			t.Fatalf("expected equal shapes: %q, %q", pair[0], pair[1])
+		}
+	}
+}
+
+func TestCollapseRepeats(t *testing.T) {
+	base := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	ev := func(id, group string, s int, msg string) TailEvent {
+		return TailEvent{ID: id, LogGroup: group, Timestamp: base.Add(time.Duration(s) * time.Second), Message: msg}
+	}
+	events := []TailEvent{
+		ev("1", "/a", 0, "retry 1"),
+		ev("2", "/b", 1, "other"),
+		ev("3", "/a", 2, "retry 2"),
+		ev("4", "/a", 3, "retry 3"),
+		ev("5", "/a", 4, "done"),
+		ev("6", "/a", 5, "retry 4"),
+		ev("7", "/b", 6, "other"),
+	}
+	out := CollapseRepeats(events)
+
+	ids := []string{}
+	for _, e := range out {
+		ids = append(ids, e.ID)
+	}
+	if len(out) != 4 || ids[0] != "1" || ids[1] != "2" || ids[2] != "5" || ids[3] != "6" {
+		t.Fatalf("unexpected collapse %v", ids)
+	}
+	if n := len(out[0].Repeats); n != 3 || out[0].Repeats[2].ID != "4" {
+		t.Fatalf("expected 3 occurrences ending with 4, got %+v", out[0].Repeats)
+	}
+	if len(out[1].Repeats) != 2 || out[3].Repeats != nil {
+		t.Fatalf("unexpected repeats %+v / %+v", out[1].Repeats, out[3].Repeats)
+	}
+	if events[0].Repeats != nil {
+		t.Fatalf("input must not be modified")
+	}
+}
@@ -0,0 +1,179 @@
+package logs
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+)
+
+// ExportRequest describes an export of one log group to S3.
+type ExportRequest struct {
+	Name     string
+	LogGroup string
+	Bucket   string
+	Prefix   string
+	From     time.Time
+	To       time.Time
+}
+
+// ExportTask is a CloudWatch Logs export-to-S3 task.
+type ExportTask struct {
+	ID            string
+	Name          string
+	LogGroup      string
+	Bucket        string
+	Prefix        string
+	Status        string
+	StatusMessage string
+	From          time.Time
+	To            time.Time
+	Created       time.Time
+	Completed     time.Time
+}
+
+// Active reports whether the task is still pending or running.
+func (t ExportTask) Active() bool {
+	switch types.ExportTaskStatusCode(t.Status) {
+	case types.ExportTaskStatusCodePending, types.ExportTaskStatusCodeRunning, types.ExportTaskStatusCodePendingCancel:
+		return true
+	}
+	return false
+}
+
+// CreateExportTask starts an export and returns the task ID.
+func (c *Client) CreateExportTask(ctx context.Context, req ExportRequest) (string, error) {
+	if req.LogGroup == "" || req.Bucket == "" {
+		return "", fmt.Errorf("create export task: log group and bucket are required")
+	}
+	if !req.To.After(req.From) {
+		return "", fmt.Errorf("create export task: end must be after start")
+	}
+	in := &cloudwatchlogs.CreateExportTaskInput{
+		LogGroupName: aws.String(req.LogGroup),
+		Destination:  aws.String(req.Bucket),
+		From:         aws.Int64(req.From.UnixMilli()),
+		To:           aws.Int64(req.To.UnixMilli()),
+	}
+	if req.Prefix != "" {
+		in.DestinationPrefix = aws.String(req.Prefix)
+	}
+	if req.Name != "" {
+		in.TaskName = aws.String(req.Name)
+	}
+	out, err := c.api.CreateExportTask(ctx, in)
+	if err != nil {
+		return "", fmt.Errorf("create export task: %w", err)
+	}
+	return aws.ToString(out.TaskId), nil
+}
+
+// ListExportTasks returns every export task, newest first.
+func (c *Client) ListExportTasks(ctx context.Context) ([]ExportTask, error) {
+	var (
+		tasks []ExportTask
+		token *string
+	)
+	for {
+		out, err := c.api.DescribeExportTasks(ctx, &cloudwatchlogs.DescribeExportTasksInput{
+			NextToken: token,
+			Limit:     aws.Int32(50),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("describe export tasks: %w", err)
+		}
+		for _, t := range out.ExportTasks {
+			tasks = append(tasks, toExportTask(t))
+		}
+		if out.NextToken == nil || aws.ToString(out.NextToken) == "" {
+			break
+		}
+		token = out.NextToken
+	}
+	sort.SliceStable(tasks, func(i, j int) bool { return tasks[i].Created.After(tasks[j].Created) })
+	return tasks, nil
+}
+
+// CancelExportTask cancels a pending or running export.
+func (c *Client) CancelExportTask(ctx context.Context, id string) error {
+	if _, err := c.api.CancelExportTask(ctx, &cloudwatchlogs.CancelExportTaskInput{TaskId: aws.String(id)}); err != nil {
+		return fmt.Errorf("cancel export task: %w", err)
+	}
+	return nil
+}
+
+func toExportTask(t types.ExportTask) ExportTask {
+	task := ExportTask{
+		ID:       aws.ToString(t.TaskId),
+		Name:     aws.ToString(t.TaskName),
+		LogGroup: aws.ToString(t.LogGroupName),
+		Bucket:   aws.ToString(t.Destination),
+		Prefix:   aws.ToString(t.DestinationPrefix),
+		From:     time.UnixMilli(aws.ToInt64(t.From)),
+		To:       time.UnixMilli(aws.ToInt64(t.To)),
+	}
+	if t.Status != nil {
+		task.Status = string(t.Status.Code)
+		task.StatusMessage = aws.ToString(t.Status.Message)
+	}
+	if t.ExecutionInfo != nil {
+		if t.ExecutionInfo.CreationTime != nil {
+			task.Created = time.UnixMilli(aws.ToInt64(t.ExecutionInfo.CreationTime))
+		}
+		if t.ExecutionInfo.CompletionTime != nil {
+			task.Completed = time.UnixMilli(aws.ToInt64(t.ExecutionInfo.CompletionTime))
+		}
+	}
+	return task
+}
+
+// ParseTime reads a point in time as typed by a user: "now", a duration ago
+// such as "90m", "24h" or "7d", a month "2024-05", a date "2024-05-31", or
+// an RFC3339 timestamp. Months and dates are interpreted in UTC and mean
+// their first instant.
+func ParseTime(value string, now time.Time) (time.Time, error) {
+	return parseTime(value, now, false)
+}
+
+// ParseEndTime reads the end of a range like ParseTime, except that months
+// and dates mean their end, the first instant of the next month or day, so
+// "2024-05" to "2024-05" covers May.
+func ParseEndTime(value string, now time.Time) (time.Time, error) {
+	return parseTime(value, now, true)
+}
+
+func parseTime(value string, now time.Time, end bool) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || value == "now" {
+		return now, nil
+	}
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if d, err := time.ParseDuration(days + "h"); err == nil {
+			return now.Add(-24 * d), nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+	if t, err := time.Parse("2006-01", value); err == nil {
+		if end {
+			t = t.AddDate(0, 1, 0)
+		}
+		return t, nil
+	}
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		if end {
+			t = t.AddDate(0, 0, 1)
+		}
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("cannot parse time %q", value)
+}
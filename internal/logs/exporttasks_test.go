@@ -0,0 +1,119 @@
+package logs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+)
+
+type fakeExportAPI struct {
+	CloudWatchLogsAPI
+
+	created *cloudwatchlogs.CreateExportTaskInput
+}
+
+func (f *fakeExportAPI) CreateExportTask(ctx context.Context, in *cloudwatchlogs.CreateExportTaskInput, _ ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.CreateExportTaskOutput, error) {
+	f.created = in
+	return &cloudwatchlogs.CreateExportTaskOutput{TaskId: aws.String("task-1")}, nil
+}
+
+func (f *fakeExportAPI) DescribeExportTasks(ctx context.Context, in *cloudwatchlogs.DescribeExportTasksInput, _ ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.DescribeExportTasksOutput, error) {
+	task := func(id string, created int64, code types.ExportTaskStatusCode) types.ExportTask {
+		return types.ExportTask{
+			TaskId:        aws.String(id),
+			Status:        &types.ExportTaskStatus{Code: code},
+			ExecutionInfo: &types.ExportTaskExecutionInfo{CreationTime: aws.Int64(created)},
+		}
+	}
+	return &cloudwatchlogs.DescribeExportTasksOutput{ExportTasks: []types.ExportTask{
+		task("old", 1000, types.ExportTaskStatusCodeCompleted),
+		task("new", 2000, types.ExportTaskStatusCodeRunning),
+	}}, nil
+}
+
+func TestCreateExportTask(t *testing.T) {
+	api := &fakeExportAPI{}
+	c := &Client{api: api}
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	id, err := c.CreateExportTask(context.Background(), ExportRequest{
+		LogGroup: "/aws/lambda/orders",
+		Bucket:   "archive",
+		Prefix:   "2024-05",
+		From:     from,
+		To:       from.AddDate(0, 1, 0),
+	})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if id != "task-1" || aws.ToString(api.created.DestinationPrefix) != "2024-05" || aws.ToInt64(api.created.From) != from.UnixMilli() {
+		t.Fatalf("unexpected request %+v", api.created)
+	}
+
+	if _, err := c.CreateExportTask(context.Background(), ExportRequest{LogGroup: "g", Bucket: "b", From: from, To: from}); err == nil {
+		t.Fatalf("empty range must be rejected")
+	}
+}
+
+func TestListExportTasksNewestFirst(t *testing.T) {
+	c := &Client{api: &fakeExportAPI{}}
+
+	tasks, err := c.ListExportTasks(context.Background())
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(tasks) != 2 || tasks[0].ID != "new" || !tasks[0].Active() || tasks[1].Active() {
+		t.Fatalf("unexpected tasks %+v", tasks)
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	cases := map[string]time.Time{
+		"now":                  now,
+		"":                     now,
+		"90m":                  now.Add(-90 * time.Minute),
+		"7d":                   now.Add(-7 * 24 * time.Hour),
+		"2024-05":              time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+		"2024-05-31":           time.Date(2024, 5, 31, 0, 0, 0, 0, time.UTC),
+		"2024-05-31T10:00:00Z": time.Date(2024, 5, 31, 10, 0, 0, 0, time.UTC),
+	}
+	for in, want := range cases {
+		got, err := ParseTime(in, now)
+		if err != nil || !got.Equal(want) {
+			t.Fatalf("%q: got %s (%v) want %s", in, got, err, want)
+		}
+	}
+	if _, err := ParseTime("yesterday-ish", now); err == nil {
+		t.Fatalf("expected parse error")
+	}
+}
+
+func TestParseEndTime(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	cases := map[string]time.Time{
+		"now":                  now,
+		"7d":                   now.Add(-7 * 24 * time.Hour),
+		"2024-05":              time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+		"2024-12":              time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		"2024-05-31":           time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+		"2024-05-31T10:00:00Z": time.Date(2024, 5, 31, 10, 0, 0, 0, time.UTC),
+	}
+	for in, want := range cases {
+		got, err := ParseEndTime(in, now)
+		if err != nil || !got.Equal(want) {
+			t.Fatalf("%q: got %s (%v) want %s", in, got, err, want)
+		}
+	}
+	// A month on both ends covers that month.
+	from, _ := ParseTime("2024-05", now)
+	to, _ := ParseEndTime("2024-05", now)
+	c := &Client{api: &fakeExportAPI{}}
+	if _, err := c.CreateExportTask(context.Background(), ExportRequest{LogGroup: "/a", Bucket: "b", From: from, To: to}); err != nil {
+		t.Fatalf("a month range must be valid: %v", err)
+	}
+}
@@ -0,0 +1,176 @@
+package logs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// FieldPath addresses a value inside a JSON log message, e.g. $.req.headers[0].
+type FieldPath struct {
+	raw      string
+	segments []pathSegment
+}
+
+type pathSegment struct {
+	key   string
+	index int
+	isIdx bool
+}
+
+// ParseFieldPath parses a JSONPath-style expression. The leading "$." is
+// optional; only member access and array indexes are supported.
+func ParseFieldPath(expr string) (FieldPath, error) {
+	raw := strings.TrimSpace(expr)
+	rest := strings.TrimPrefix(strings.TrimPrefix(raw, "$"), ".")
+	if rest == "" {
+		return FieldPath{}, fmt.Errorf("empty field path")
+	}
+	var segs []pathSegment
+	for _, part := range strings.Split(rest, ".") {
+		key := part
+		var idxs []int
+		if open := strings.IndexByte(part, '['); open >= 0 {
+			key = part[:open]
+			for tail := part[open:]; tail != ""; {
+				end := strings.IndexByte(tail, ']')
+				if tail[0] != '[' || end < 0 {
+					return FieldPath{}, fmt.Errorf("invalid index in %q", part)
+				}
+				n, err := strconv.Atoi(tail[1:end])
+				if err != nil || n < 0 {
+					return FieldPath{}, fmt.Errorf("invalid index in %q", part)
+				}
+				idxs = append(idxs, n)
+				tail = tail[end+1:]
+			}
+		}
+		if key == "" && len(idxs) == 0 {
+			return FieldPath{}, fmt.Errorf("empty segment in %q", expr)
+		}
+		if key != "" {
+			segs = append(segs, pathSegment{key: key})
+		}
+		for _, n := range idxs {
+			segs = append(segs, pathSegment{index: n, isIdx: true})
+		}
+	}
+	return FieldPath{raw: "$." + rest, segments: segs}, nil
+}
+
+func (p FieldPath) String() string {
+	return p.raw
+}
+
+// Lookup extracts the value at p from a message. Messages may carry a plain
+// text prefix (as Lambda runtimes add) before the JSON object.
+func (p FieldPath) Lookup(message string) (string, bool) {
+	doc, ok := parseJSONMessage(message)
+	if !ok {
+		return "", false
+	}
+	cur := doc
+	for _, seg := range p.segments {
+		if seg.isIdx {
+			arr, ok := cur.([]interface{})
+			if !ok || seg.index >= len(arr) {
+				return "", false
+			}
+			cur = arr[seg.index]
+			continue
+		}
+		obj, ok := cur.(map[string]interface{})
+		if !ok {
+			return "", false
+		}
+		if cur, ok = obj[seg.key]; !ok {
+			return "", false
+		}
+	}
+	return formatJSONValue(cur), true
+}
+
+func parseJSONMessage(message string) (interface{}, bool) {
+	start := strings.IndexByte(message, '{')
+	if start < 0 {
+		return nil, false
+	}
+	dec := json.NewDecoder(strings.NewReader(message[start:]))
+	dec.UseNumber()
+	var doc interface{}
+	if err := dec.Decode(&doc); err != nil {
+		return nil, false
+	}
+	return doc, true
+}
+
+func formatJSONValue(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return val
+	case json.Number:
+		return val.String()
+	case bool:
+		return strconv.FormatBool(val)
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return fmt.Sprint(v)
+	}
+	return strings.TrimSpace(buf.String())
+}
+
+// ValueCount is one entry of a top-N breakdown.
+type ValueCount struct {
+	Value   string
+	Count   int
+	Percent float64
+}
+
+// FieldStats is the top-N breakdown of a field across events.
+type FieldStats struct {
+	Path    FieldPath
+	Total   int // events considered
+	Matched int // events containing the field
+	Values  []ValueCount
+}
+
+// TopValues counts the values of path across events and returns the n most
+// frequent ones, ties broken by value. Percentages are relative to the events
+// that contain the field. n <= 0 returns every value.
+func TopValues(events []TailEvent, path FieldPath, n int) FieldStats {
+	stats := FieldStats{Path: path, Total: len(events)}
+	counts := map[string]int{}
+	for _, e := range events {
+		v, ok := path.Lookup(e.Message)
+		if !ok {
+			continue
+		}
+		stats.Matched++
+		counts[v]++
+	}
+	for v, c := range counts {
+		stats.Values = append(stats.Values, ValueCount{Value: v, Count: c})
+	}
+	sort.Slice(stats.Values, func(i, j int) bool {
+		a, b := stats.Values[i], stats.Values[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Value < b.Value
+	})
+	if n > 0 && len(stats.Values) > n {
+		stats.Values = stats.Values[:n]
+	}
+	for i := range stats.Values {
+		stats.Values[i].Percent = 100 * float64(stats.Values[i].Count) / float64(stats.Matched)
+	}
+	return stats
+}
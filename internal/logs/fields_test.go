@@ -0,0 +1,86 @@
+package logs
+
+import "testing"
+
+func TestFieldPathLookup(t *testing.T) {
+	msg := `2024-01-01T00:00:00Z abc INFO {"statusCode":200,"user":{"id":"u-1","roles":["admin","dev"]},"ok":true,"price":1.50}`
+	cases := map[string]string{
+		"$.statusCode":    "200",
+		"user.id":         "u-1",
+		"$.user.roles[1]": "dev",
+		"$.ok":            "true",
+		"$.price":         "1.50",
+		"$.user.roles":    `["admin","dev"]`,
+	}
+	for expr, want := range cases {
+		path, err := ParseFieldPath(expr)
+		if err != nil {
+			t.Fatalf("parse %s: %v", expr, err)
+		}
+		got, ok := path.Lookup(msg)
+		if !ok || got != want {
+			t.Fatalf("%s: got %q (found=%v) want %q", expr, got, ok, want)
+		}
+	}
+
+	path, _ := ParseFieldPath("$.missing")
+	if _, ok := path.Lookup(msg); ok {
+		t.Fatalf("missing field must not be found")
+	}
+	if _, err := ParseFieldPath("$.a[x]"); err == nil {
+		t.Fatalf("expected error for invalid index")
+	}
+}
+
+func TestTopValues(t *testing.T) {
+	events := []TailEvent{
+		{Message: `{"statusCode":200}`},
+		{Message: `{"statusCode":500}`},
+		{Message: `{"statusCode":200}`},
+		{Message: `{"statusCode":404}`},
+		{Message: `plain text`},
+	}
+	path, _ := ParseFieldPath("$.statusCode")
+
+	stats := TopValues(events, path, 2)
+
+	if stats.Total != 5 || stats.Matched != 4 {
+		t.Fatalf("unexpected totals %+v", stats)
+	}
+	if len(stats.Values) != 2 {
+		t.Fatalf("expected top 2, got %d", len(stats.Values))
+	}
+	if stats.Values[0].Value != "200" || stats.Values[0].Count != 2 || stats.Values[0].Percent != 50 {
+		t.Fatalf("unexpected top value %+v", stats.Values[0])
+	}
+	if stats.Values[1].Value != "404" {
+		t.Fatalf("ties must be ordered by value, got %+v", stats.Values[1])
+	}
+}
+
+func TestFilter(t *testing.T) {
+	events := []TailEvent{
+		{Message: `{"statusCode":200,"msg":"OK"}`},
+		{Message: `{"statusCode":500,"msg":"Boom"}`},
+	}
+	f, err := ParseFilter("$.statusCode=500")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if got := f.Apply(events); len(got) != 1 || got[0].Message != events[1].Message {
+		t.Fatalf("field filter mismatch: %+v", got)
+	}
+
+	f, _ = ParseFilter("boom")
+	if got := f.Apply(events); len(got) != 1 {
+		t.Fatalf("text filter must be case-insensitive, got %d", len(got))
+	}
+
+	f, _ = ParseFilter("")
+	if got := f.Apply(events); len(got) != 2 {
+		t.Fatalf("empty filter must match everything")
+	}
+	if _, err := ParseFilter("$.statusCode"); err == nil {
+		t.Fatalf("field filter without value must fail")
+	}
+}
@@ -0,0 +1,276 @@
+package logs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FileSource serves local log files as pseudo log groups named after their
+// path. It reads plain text, NDJSON written by WriteNDJSON, and the JSON
+// output of `aws logs filter-log-events` or `get-log-events`.
+type FileSource struct {
+	mu    sync.Mutex
+	files map[string][]TailEvent
+	order []string
+	sizes map[string]int64
+}
+
+func NewFileSource() *FileSource {
+	return &FileSource{files: map[string][]TailEvent{}, sizes: map[string]int64{}}
+}
+
+// Open reads a file and adds it as a log group. Opening a path again
+// reloads it.
+func (s *FileSource) Open(path string) (LogGroup, error) {
+	name := filepath.Clean(path)
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return LogGroup{}, fmt.Errorf("open %s: %w", name, err)
+	}
+	var modTime time.Time
+	if info, err := os.Stat(name); err == nil {
+		modTime = info.ModTime()
+	}
+	events, err := ParseLogFile(name, data, modTime)
+	if err != nil {
+		return LogGroup{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.files[name]; !ok {
+		s.order = append(s.order, name)
+	}
+	s.files[name] = events
+	s.sizes[name] = int64(len(data))
+	return LogGroup{Name: name, StoredBytes: int64(len(data))}, nil
+}
+
+// Close forgets a file.
+func (s *FileSource) Close(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.files, name)
+	delete(s.sizes, name)
+	for i, n := range s.order {
+		if n == name {
+			s.order = append(s.order[:i], s.order[i+1:]...)
+			break
+		}
+	}
+}
+
+// Len reports how many files are open.
+func (s *FileSource) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.order)
+}
+
+// ListLogGroups returns every open file in the order it was opened.
+func (s *FileSource) ListLogGroups(ctx context.Context, nextToken *string) ([]LogGroup, *string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	groups := make([]LogGroup, 0, len(s.order))
+	for _, name := range s.order {
+		groups = append(groups, LogGroup{Name: name, StoredBytes: s.sizes[name]})
+	}
+	return groups, nil, nil
+}
+
+// FetchEvents returns the events at or after start. Files do not grow, so
+// the next start is just past the newest event returned.
+func (s *FileSource) FetchEvents(ctx context.Context, groups []string, start time.Time) ([]TailEvent, time.Time, error) {
+	events, err := s.collect(groups, func(e TailEvent) bool { return !e.Timestamp.Before(start) })
+	if err != nil {
+		return nil, start, err
+	}
+	next := start
+	if len(events) > 0 {
+		next = events[len(events)-1].Timestamp.Add(time.Millisecond)
+	}
+	return events, next, nil
+}
+
+// SearchEvents supports the plain-text filter patterns of MatchPattern. It
+// reads whole files, so it is never truncated.
+func (s *FileSource) SearchEvents(ctx context.Context, groups []string, pattern string, start, end time.Time) ([]TailEvent, bool, error) {
+	match := MatchPattern(pattern)
+	events, err := s.collect(groups, func(e TailEvent) bool {
+		return !e.Timestamp.Before(start) && !e.Timestamp.After(end) && match(e.Message)
+	})
+	return events, false, err
+}
+
+func (s *FileSource) collect(groups []string, keep func(TailEvent) bool) ([]TailEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var batches [][]TailEvent
+	for _, g := range groups {
+		all, ok := s.files[g]
+		if !ok {
+			return nil, fmt.Errorf("%s is not open", g)
+		}
+		var batch []TailEvent
+		for _, e := range all {
+			if keep(e) {
+				batch = append(batch, e)
+			}
+		}
+		batches = append(batches, batch)
+	}
+	return MergeEvents(batches...), nil
+}
+
+// cliOutput is the JSON printed by `aws logs filter-log-events` and
+// `aws logs get-log-events`.
+type cliOutput struct {
+	Events *[]struct {
+		LogStreamName string `json:"logStreamName"`
+		Timestamp     int64  `json:"timestamp"`
+		Message       string `json:"message"`
+		IngestionTime int64  `json:"ingestionTime"`
+		EventID       string `json:"eventId"`
+	} `json:"events"`
+}
+
+// ParseLogFile detects the format of a log file and returns its events
+// ordered by timestamp, all in the log group named group. Plain-text lines
+// without a leading timestamp inherit the previous line's, or fallback for
+// the first lines.
+func ParseLogFile(group string, data []byte, fallback time.Time) ([]TailEvent, error) {
+	trimmed := bytes.TrimSpace(data)
+	var events []TailEvent
+	switch {
+	case isCLIOutput(trimmed):
+		var out cliOutput
+		if err := json.Unmarshal(trimmed, &out); err != nil {
+			return nil, fmt.Errorf("parse %s: %w", group, err)
+		}
+		for i, e := range *out.Events {
+			event := TailEvent{
+				ID:        e.EventID,
+				Timestamp: time.UnixMilli(e.Timestamp),
+				LogGroup:  group,
+				LogStream: e.LogStreamName,
+				Message:   strings.TrimRight(e.Message, "\n"),
+			}
+			if event.ID == "" {
+				event.ID = strconv.Itoa(i + 1)
+			}
+			if e.IngestionTime > 0 {
+				event.IngestionTime = time.UnixMilli(e.IngestionTime)
+			}
+			events = append(events, event)
+		}
+	case isNDJSON(trimmed):
+		for i, line := range bytes.Split(trimmed, []byte("\n")) {
+			line = bytes.TrimSpace(line)
+			if len(line) == 0 {
+				continue
+			}
+			var e ndjsonEvent
+			if err := json.Unmarshal(line, &e); err != nil {
+				return nil, fmt.Errorf("parse %s line %d: %w", group, i+1, err)
+			}
+			event := TailEvent{
+				ID:        e.ID,
+				Timestamp: e.Timestamp,
+				LogGroup:  group,
+				LogStream: e.LogStream,
+				Message:   e.Message,
+			}
+			if event.LogStream == "" {
+				event.LogStream = e.LogGroup
+			}
+			if event.ID == "" {
+				event.ID = strconv.Itoa(i + 1)
+			}
+			if e.IngestionTime != nil {
+				event.IngestionTime = *e.IngestionTime
+			}
+			events = append(events, event)
+		}
+	default:
+		last := fallback
+		for i, line := range strings.Split(string(trimmed), "\n") {
+			line = strings.TrimRight(line, "\r")
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+			if ts, ok := leadingTimestamp(line); ok {
+				last = ts
+			}
+			events = append(events, TailEvent{
+				ID:        strconv.Itoa(i + 1),
+				Timestamp: last,
+				LogGroup:  group,
+				LogStream: filepath.Base(group),
+				Message:   line,
+			})
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Timestamp.Before(events[j].Timestamp) })
+	return events, nil
+}
+
+func isCLIOutput(data []byte) bool {
+	if len(data) == 0 || data[0] != '{' {
+		return false
+	}
+	var out cliOutput
+	return json.Unmarshal(data, &out) == nil && out.Events != nil
+}
+
+// isNDJSON reports whether the first line looks like a sacha export; other
+// JSON lines are kept as plain-text messages so their fields stay intact.
+func isNDJSON(data []byte) bool {
+	first, _, _ := bytes.Cut(data, []byte("\n"))
+	var probe map[string]json.RawMessage
+	if json.Unmarshal(first, &probe) != nil {
+		return false
+	}
+	_, hasGroup := probe["logGroup"]
+	_, hasMessage := probe["message"]
+	_, hasTimestamp := probe["timestamp"]
+	return hasGroup && hasMessage && hasTimestamp
+}
+
+var lineTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.000Z07:00",
+	"2006-01-02 15:04:05.000",
+	"2006-01-02 15:04:05,000",
+	"2006-01-02 15:04:05",
+}
+
+// leadingTimestamp parses a timestamp at the start of a line, optionally
+// wrapped in brackets.
+func leadingTimestamp(line string) (time.Time, bool) {
+	line = strings.TrimPrefix(line, "[")
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return time.Time{}, false
+	}
+	candidates := []string{strings.TrimRight(fields[0], "]")}
+	if len(fields) > 1 {
+		candidates = append(candidates, fields[0]+" "+strings.TrimRight(fields[1], "]"))
+	}
+	for _, c := range candidates {
+		for _, layout := range lineTimeLayouts {
+			if t, err := time.Parse(layout, c); err == nil {
+				return t, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
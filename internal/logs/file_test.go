@@ -0,0 +1,107 @@
+package logs
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseLogFileCLIOutput(t *testing.T) {
+	data := []byte(`{
+  "events": [
+    {"logStreamName": "b", "timestamp": 2000, "message": "second\n", "ingestionTime": 2500, "eventId": "e2"},
+    {"logStreamName": "a", "timestamp": 1000, "message": "first", "ingestionTime": 1100, "eventId": "e1"}
+  ],
+  "searchedLogStreams": []
+}`)
+	events, err := ParseLogFile("dump.json", data, time.Time{})
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if len(events) != 2 || events[0].ID != "e1" || events[1].Message != "second" || events[1].Lag() != 500*time.Millisecond {
+		t.Fatalf("unexpected events %+v", events)
+	}
+	if events[0].LogGroup != "dump.json" || events[0].LogStream != "a" {
+		t.Fatalf("unexpected group or stream %+v", events[0])
+	}
+}
+
+func TestParseLogFileNDJSONRoundTrip(t *testing.T) {
+	ts := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	in := []TailEvent{
+		{ID: "1", Timestamp: ts, LogGroup: "/app", LogStream: "s1", Message: `{"level":"info"}`, IngestionTime: ts.Add(time.Second)},
+		{ID: "2", Timestamp: ts.Add(time.Minute), LogGroup: "/app", LogStream: "s2", Message: "plain"},
+	}
+	var buf bytes.Buffer
+	if err := WriteNDJSON(&buf, in); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	out, err := ParseLogFile("export.ndjson", buf.Bytes(), time.Time{})
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if len(out) != 2 || out[0].Message != in[0].Message || !out[0].IngestionTime.Equal(in[0].IngestionTime) || out[1].LogStream != "s2" {
+		t.Fatalf("unexpected events %+v", out)
+	}
+}
+
+func TestParseLogFilePlainText(t *testing.T) {
+	fallback := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	data := []byte("preamble\n2024-05-01T10:00:00Z start\n  at Foo.bar\n\n[2024-05-01 10:00:05] done\n{\"logGroup\":1}\n")
+	events, err := ParseLogFile("app.log", data, fallback)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if len(events) != 5 {
+		t.Fatalf("expected 5 events, got %+v", events)
+	}
+	if !events[0].Timestamp.Equal(fallback) {
+		t.Fatalf("first line should use the fallback time, got %s", events[0].Timestamp)
+	}
+	if !events[2].Timestamp.Equal(events[1].Timestamp) || events[2].Message != "  at Foo.bar" {
+		t.Fatalf("continuation should inherit the timestamp: %+v", events[2])
+	}
+	if want := time.Date(2024, 5, 1, 10, 0, 5, 0, time.UTC); !events[3].Timestamp.Equal(want) {
+		t.Fatalf("bracketed timestamp: got %s", events[3].Timestamp)
+	}
+}
+
+func TestFileSource(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	data := "2024-05-01T10:00:00Z req-1 start\n2024-05-01T10:00:01Z req-2 start\n2024-05-01T10:00:02Z req-1 done\n"
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	src := NewFileSource()
+	group, err := src.Open(path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	groups, next, err := src.ListLogGroups(context.Background(), nil)
+	if err != nil || next != nil || len(groups) != 1 || groups[0].Name != group.Name {
+		t.Fatalf("unexpected groups %+v %v", groups, err)
+	}
+
+	ctx := context.Background()
+	events, resume, err := src.FetchEvents(ctx, []string{group.Name}, time.Time{})
+	if err != nil || len(events) != 3 {
+		t.Fatalf("fetch: %v %+v", err, events)
+	}
+	if more, _, _ := src.FetchEvents(ctx, []string{group.Name}, resume); len(more) != 0 {
+		t.Fatalf("expected nothing after resume, got %+v", more)
+	}
+
+	found, _, err := src.SearchEvents(ctx, []string{group.Name}, `"req-1"`, time.Time{}, time.Now())
+	if err != nil || len(found) != 2 {
+		t.Fatalf("search: %v %+v", err, found)
+	}
+
+	src.Close(group.Name)
+	if _, _, err := src.FetchEvents(ctx, []string{group.Name}, time.Time{}); err == nil {
+		t.Fatalf("closed file must not be readable")
+	}
+}
@@ -0,0 +1,74 @@
+package logs
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Filter selects events from the tail buffer.
+type Filter struct {
+	expr  string
+	text  string
+	path  *FieldPath
+	value string
+}
+
+// ParseFilter accepts either "$.path=value" to match a JSON field exactly or
+// any other text to match messages case-insensitively. An empty expression
+// matches everything.
+func ParseFilter(expr string) (Filter, error) {
+	expr = strings.TrimSpace(expr)
+	f := Filter{expr: expr}
+	if strings.HasPrefix(expr, "$.") {
+		eq := strings.IndexByte(expr, '=')
+		if eq < 0 {
+			return Filter{}, fmt.Errorf("field filter %q needs =value", expr)
+		}
+		path, err := ParseFieldPath(expr[:eq])
+		if err != nil {
+			return Filter{}, err
+		}
+		f.path = &path
+		f.value = strings.TrimSpace(expr[eq+1:])
+		return f, nil
+	}
+	f.text = strings.ToLower(expr)
+	return f, nil
+}
+
+// FieldFilter matches events whose field at path equals value.
+func FieldFilter(path FieldPath, value string) Filter {
+	return Filter{expr: path.String() + "=" + value, path: &path, value: value}
+}
+
+func (f Filter) String() string {
+	return f.expr
+}
+
+// Empty reports whether the filter matches everything.
+func (f Filter) Empty() bool {
+	return f.expr == ""
+}
+
+// Match reports whether e passes the filter.
+func (f Filter) Match(e TailEvent) bool {
+	if f.path != nil {
+		v, ok := f.path.Lookup(e.Message)
+		return ok && v == f.value
+	}
+	return strings.Contains(strings.ToLower(e.Message), f.text)
+}
+
+// Apply returns the events passing the filter.
+func (f Filter) Apply(events []TailEvent) []TailEvent {
+	if f.Empty() {
+		return events
+	}
+	out := make([]TailEvent, 0, len(events))
+	for _, e := range events {
+		if f.Match(e) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
@@ -0,0 +1,161 @@
+package logs
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+)
+
+// queryPollInterval is how often RunQuery checks for results.
+var queryPollInterval = time.Second
+
+// QueryDefinition is a saved Logs Insights query.
+type QueryDefinition struct {
+	ID           string
+	Name         string
+	Query        string
+	LogGroups    []string
+	LastModified time.Time
+}
+
+// QueryResult holds the rows of a finished Logs Insights query. Columns are
+// ordered by first appearance; the internal @ptr field is dropped.
+type QueryResult struct {
+	Status         string
+	Fields         []string
+	Rows           [][]string
+	RecordsMatched float64
+	BytesScanned   float64
+}
+
+// ListQueryDefinitions returns every saved query, sorted by name.
+func (c *Client) ListQueryDefinitions(ctx context.Context) ([]QueryDefinition, error) {
+	var (
+		defs  []QueryDefinition
+		token *string
+	)
+	for {
+		out, err := c.api.DescribeQueryDefinitions(ctx, &cloudwatchlogs.DescribeQueryDefinitionsInput{
+			NextToken:  token,
+			MaxResults: aws.Int32(1000),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("describe query definitions: %w", err)
+		}
+		for _, d := range out.QueryDefinitions {
+			def := QueryDefinition{
+				ID:        aws.ToString(d.QueryDefinitionId),
+				Name:      aws.ToString(d.Name),
+				Query:     aws.ToString(d.QueryString),
+				LogGroups: append([]string(nil), d.LogGroupNames...),
+			}
+			if d.LastModified != nil {
+				def.LastModified = time.UnixMilli(aws.ToInt64(d.LastModified))
+			}
+			defs = append(defs, def)
+		}
+		if out.NextToken == nil || aws.ToString(out.NextToken) == "" {
+			break
+		}
+		token = out.NextToken
+	}
+	sort.SliceStable(defs, func(i, j int) bool { return defs[i].Name < defs[j].Name })
+	return defs, nil
+}
+
+// SaveQueryDefinition creates the definition when ID is empty and updates it
+// otherwise. It returns the definition ID.
+func (c *Client) SaveQueryDefinition(ctx context.Context, def QueryDefinition) (string, error) {
+	in := &cloudwatchlogs.PutQueryDefinitionInput{
+		Name:          aws.String(def.Name),
+		QueryString:   aws.String(def.Query),
+		LogGroupNames: def.LogGroups,
+	}
+	if def.ID != "" {
+		in.QueryDefinitionId = aws.String(def.ID)
+	}
+	out, err := c.api.PutQueryDefinition(ctx, in)
+	if err != nil {
+		return "", fmt.Errorf("put query definition: %w", err)
+	}
+	return aws.ToString(out.QueryDefinitionId), nil
+}
+
+// DeleteQueryDefinition removes a saved query.
+func (c *Client) DeleteQueryDefinition(ctx context.Context, id string) error {
+	if _, err := c.api.DeleteQueryDefinition(ctx, &cloudwatchlogs.DeleteQueryDefinitionInput{
+		QueryDefinitionId: aws.String(id),
+	}); err != nil {
+		return fmt.Errorf("delete query definition: %w", err)
+	}
+	return nil
+}
+
+// RunQuery starts a Logs Insights query and waits until it finishes or ctx
+// is done.
+func (c *Client) RunQuery(ctx context.Context, groups []string, query string, start, end time.Time) (QueryResult, error) {
+	if len(groups) == 0 {
+		return QueryResult{}, fmt.Errorf("start query: no log groups")
+	}
+	started, err := c.api.StartQuery(ctx, &cloudwatchlogs.StartQueryInput{
+		LogGroupNames: groups,
+		QueryString:   aws.String(query),
+		StartTime:     aws.Int64(start.Unix()),
+		EndTime:       aws.Int64(end.Unix()),
+	})
+	if err != nil {
+		return QueryResult{}, fmt.Errorf("start query: %w", err)
+	}
+
+	for {
+		out, err := c.api.GetQueryResults(ctx, &cloudwatchlogs.GetQueryResultsInput{QueryId: started.QueryId})
+		if err != nil {
+			return QueryResult{}, fmt.Errorf("get query results: %w", err)
+		}
+		switch out.Status {
+		case types.QueryStatusComplete:
+			return toQueryResult(out), nil
+		case types.QueryStatusFailed, types.QueryStatusCancelled, types.QueryStatusTimeout:
+			return QueryResult{}, fmt.Errorf("query %s", out.Status)
+		}
+		select {
+		case <-ctx.Done():
+			return QueryResult{}, ctx.Err()
+		case <-time.After(queryPollInterval):
+		}
+	}
+}
+
+func toQueryResult(out *cloudwatchlogs.GetQueryResultsOutput) QueryResult {
+	res := QueryResult{Status: string(out.Status)}
+	if out.Statistics != nil {
+		res.RecordsMatched = out.Statistics.RecordsMatched
+		res.BytesScanned = out.Statistics.BytesScanned
+	}
+	columns := map[string]int{}
+	for _, row := range out.Results {
+		for _, f := range row {
+			name := aws.ToString(f.Field)
+			if _, ok := columns[name]; ok || name == "@ptr" {
+				continue
+			}
+			columns[name] = len(res.Fields)
+			res.Fields = append(res.Fields, name)
+		}
+	}
+	for _, row := range out.Results {
+		values := make([]string, len(res.Fields))
+		for _, f := range row {
+			if idx, ok := columns[aws.ToString(f.Field)]; ok {
+				values[idx] = aws.ToString(f.Value)
+			}
+		}
+		res.Rows = append(res.Rows, values)
+	}
+	return res
+}
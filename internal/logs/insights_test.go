@@ -0,0 +1,120 @@
+package logs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+)
+
+type fakeInsightsAPI struct {
+	CloudWatchLogsAPI
+
+	put      *cloudwatchlogs.PutQueryDefinitionInput
+	polls    int
+	queryIDs []string
+}
+
+func (f *fakeInsightsAPI) DescribeQueryDefinitions(ctx context.Context, in *cloudwatchlogs.DescribeQueryDefinitionsInput, _ ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.DescribeQueryDefinitionsOutput, error) {
+	if in.NextToken == nil {
+		return &cloudwatchlogs.DescribeQueryDefinitionsOutput{
+			QueryDefinitions: []types.QueryDefinition{{QueryDefinitionId: aws.String("2"), Name: aws.String("errors")}},
+			NextToken:        aws.String("page-2"),
+		}, nil
+	}
+	return &cloudwatchlogs.DescribeQueryDefinitionsOutput{
+		QueryDefinitions: []types.QueryDefinition{{
+			QueryDefinitionId: aws.String("1"),
+			Name:              aws.String("cold starts"),
+			QueryString:       aws.String("filter @type = \"REPORT\""),
+			LogGroupNames:     []string{"/aws/lambda/orders"},
+		}},
+	}, nil
+}
+
+func (f *fakeInsightsAPI) PutQueryDefinition(ctx context.Context, in *cloudwatchlogs.PutQueryDefinitionInput, _ ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.PutQueryDefinitionOutput, error) {
+	f.put = in
+	return &cloudwatchlogs.PutQueryDefinitionOutput{QueryDefinitionId: aws.String("new-id")}, nil
+}
+
+func (f *fakeInsightsAPI) StartQuery(ctx context.Context, in *cloudwatchlogs.StartQueryInput, _ ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.StartQueryOutput, error) {
+	return &cloudwatchlogs.StartQueryOutput{QueryId: aws.String("q-1")}, nil
+}
+
+func (f *fakeInsightsAPI) GetQueryResults(ctx context.Context, in *cloudwatchlogs.GetQueryResultsInput, _ ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.GetQueryResultsOutput, error) {
+	f.polls++
+	f.queryIDs = append(f.queryIDs, aws.ToString(in.QueryId))
+	if f.polls == 1 {
+		return &cloudwatchlogs.GetQueryResultsOutput{Status: types.QueryStatusRunning}, nil
+	}
+	field := func(name, value string) types.ResultField {
+		return types.ResultField{Field: aws.String(name), Value: aws.String(value)}
+	}
+	return &cloudwatchlogs.GetQueryResultsOutput{
+		Status: types.QueryStatusComplete,
+		Results: [][]types.ResultField{
+			{field("@timestamp", "t1"), field("@message", "m1"), field("@ptr", "p1")},
+			{field("@timestamp", "t2"), field("count", "3")},
+		},
+	}, nil
+}
+
+func TestListQueryDefinitionsPaginatesAndSorts(t *testing.T) {
+	c := &Client{api: &fakeInsightsAPI{}}
+
+	defs, err := c.ListQueryDefinitions(context.Background())
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(defs) != 2 || defs[0].Name != "cold starts" || defs[1].Name != "errors" {
+		t.Fatalf("unexpected definitions %+v", defs)
+	}
+	if len(defs[0].LogGroups) != 1 || defs[0].Query == "" {
+		t.Fatalf("definition fields not mapped: %+v", defs[0])
+	}
+}
+
+func TestSaveQueryDefinitionCreatesWithoutID(t *testing.T) {
+	api := &fakeInsightsAPI{}
+	c := &Client{api: api}
+
+	id, err := c.SaveQueryDefinition(context.Background(), QueryDefinition{Name: "n", Query: "fields @message"})
+	if err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if id != "new-id" || api.put.QueryDefinitionId != nil {
+		t.Fatalf("expected create without ID, got id=%s input=%+v", id, api.put)
+	}
+}
+
+func TestRunQueryPollsUntilComplete(t *testing.T) {
+	prev := queryPollInterval
+	queryPollInterval = time.Millisecond
+	defer func() { queryPollInterval = prev }()
+
+	api := &fakeInsightsAPI{}
+	c := &Client{api: api}
+
+	res, err := c.RunQuery(context.Background(), []string{"g"}, "fields @message", time.Now().Add(-time.Hour), time.Now())
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if api.polls != 2 || api.queryIDs[1] != "q-1" {
+		t.Fatalf("expected two polls of q-1, got %v", api.queryIDs)
+	}
+	wantFields := []string{"@timestamp", "@message", "count"}
+	if len(res.Fields) != len(wantFields) {
+		t.Fatalf("fields: got %v want %v", res.Fields, wantFields)
+	}
+	for i, f := range wantFields {
+		if res.Fields[i] != f {
+			t.Fatalf("fields: got %v want %v", res.Fields, wantFields)
+		}
+	}
+	if len(res.Rows) != 2 || res.Rows[1][2] != "3" || res.Rows[1][1] != "" {
+		t.Fatalf("unexpected rows %v", res.Rows)
+	}
+}
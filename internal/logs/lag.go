@@ -0,0 +1,92 @@
+package logs
+
+import (
+	"sort"
+	"time"
+)
+
+// DefaultLagWindow is the number of samples kept per group.
+const DefaultLagWindow = 200
+
+// LagStats summarizes recent ingestion lag for one group.
+type LagStats struct {
+	P50     time.Duration
+	P95     time.Duration
+	Samples int
+	// Growing is set when the newer half of the window lags noticeably more
+	// than the older half, which usually means a shipper is falling behind.
+	Growing bool
+}
+
+// LagTracker keeps a rolling window of ingestion lag samples per group.
+type LagTracker struct {
+	window  int
+	samples map[string][]time.Duration
+}
+
+// NewLagTracker returns a tracker keeping up to window samples per group.
+func NewLagTracker(window int) *LagTracker {
+	if window <= 0 {
+		window = DefaultLagWindow
+	}
+	return &LagTracker{window: window, samples: map[string][]time.Duration{}}
+}
+
+// Observe records the lag of every event with a known ingestion time, keyed
+// by the value returned from key.
+func (t *LagTracker) Observe(events []TailEvent, key func(TailEvent) string) {
+	for _, e := range events {
+		if e.IngestionTime.IsZero() {
+			continue
+		}
+		k := key(e)
+		s := append(t.samples[k], e.Lag())
+		if len(s) > t.window {
+			s = s[len(s)-t.window:]
+		}
+		t.samples[k] = s
+	}
+}
+
+// Keys returns the tracked keys in sorted order.
+func (t *LagTracker) Keys() []string {
+	keys := make([]string, 0, len(t.samples))
+	for k := range t.samples {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Stats reports the lag percentiles for key; ok is false without samples.
+func (t *LagTracker) Stats(key string) (LagStats, bool) {
+	s := t.samples[key]
+	if len(s) == 0 {
+		return LagStats{}, false
+	}
+	stats := LagStats{
+		P50:     percentile(s, 50),
+		P95:     percentile(s, 95),
+		Samples: len(s),
+	}
+	if len(s) >= 10 {
+		older := percentile(s[:len(s)/2], 50)
+		newer := percentile(s[len(s)/2:], 50)
+		stats.Growing = newer-older > 5*time.Second && newer > older*3/2
+	}
+	return stats, true
+}
+
+// percentile returns the nearest-rank percentile p (0-100) of samples.
+func percentile(samples []time.Duration, p int) time.Duration {
+	sorted := append([]time.Duration(nil), samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	idx := (p*len(sorted)+99)/100 - 1
+	if idx < 0 {
+		idx = 0
+	}
+	if idx >= len(sorted) {
+		idx = len(sorted) - 1
+	}
+	return sorted[idx]
+}
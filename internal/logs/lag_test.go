@@ -0,0 +1,62 @@
+package logs
+
+import (
+	"testing"
+	"time"
+)
+
+func lagEvents(group string, lags ...time.Duration) []TailEvent {
+	base := time.Unix(1700000000, 0)
+	events := make([]TailEvent, 0, len(lags))
+	for i, lag := range lags {
+		ts := base.Add(time.Duration(i) * time.Second)
+		events = append(events, TailEvent{LogGroup: group, Timestamp: ts, IngestionTime: ts.Add(lag)})
+	}
+	return events
+}
+
+func byGroup(e TailEvent) string { return e.LogGroup }
+
+func TestLagTrackerPercentiles(t *testing.T) {
+	tracker := NewLagTracker(0)
+	lags := make([]time.Duration, 0, 20)
+	for i := 1; i <= 20; i++ {
+		lags = append(lags, time.Duration(i)*100*time.Millisecond)
+	}
+	tracker.Observe(lagEvents("api", lags...), byGroup)
+	tracker.Observe([]TailEvent{{LogGroup: "api", Timestamp: time.Now()}}, byGroup)
+
+	stats, ok := tracker.Stats("api")
+	if !ok {
+		t.Fatalf("expected stats for api")
+	}
+	if stats.Samples != 20 {
+		t.Fatalf("events without ingestion time must be ignored, got %d samples", stats.Samples)
+	}
+	if stats.P50 != time.Second || stats.P95 != 1900*time.Millisecond {
+		t.Fatalf("unexpected percentiles p50=%s p95=%s", stats.P50, stats.P95)
+	}
+	if stats.Growing {
+		t.Fatalf("steady sub-second lag must not be flagged as growing")
+	}
+}
+
+func TestLagTrackerDetectsGrowth(t *testing.T) {
+	tracker := NewLagTracker(10)
+	lags := []time.Duration{}
+	for i := 0; i < 5; i++ {
+		lags = append(lags, time.Second)
+	}
+	for i := 0; i < 5; i++ {
+		lags = append(lags, 30*time.Second)
+	}
+	tracker.Observe(lagEvents("worker", lags...), byGroup)
+
+	stats, _ := tracker.Stats("worker")
+	if !stats.Growing {
+		t.Fatalf("expected growing lag, got %+v", stats)
+	}
+	if _, ok := tracker.Stats("missing"); ok {
+		t.Fatalf("unknown key must report no stats")
+	}
+}
@@ -0,0 +1,210 @@
+package logs
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LambdaGroupPrefix is the log group prefix used by AWS Lambda functions.
+const LambdaGroupPrefix = "/aws/lambda/"
+
+// IsLambdaGroup reports whether a log group belongs to a Lambda function.
+func IsLambdaGroup(name string) bool {
+	return strings.HasPrefix(name, LambdaGroupPrefix)
+}
+
+// Invocation is one Lambda request reconstructed from its platform lines.
+type Invocation struct {
+	RequestID       string
+	LogGroup        string
+	LogStream       string
+	Start           time.Time
+	Duration        time.Duration
+	BilledDuration  time.Duration
+	InitDuration    time.Duration
+	MemorySizeMB    int
+	MaxMemoryUsedMB int
+	ColdStart       bool
+	TimedOut        bool
+	// Anchor is the first event of the invocation (START, or REPORT when
+	// START is outside the buffer), used to jump to its log lines.
+	Anchor TailEvent
+}
+
+var (
+	lambdaStartRe    = regexp.MustCompile(`^START RequestId: (\S+)`)
+	lambdaReportRe   = regexp.MustCompile(`^REPORT RequestId: (\S+)`)
+	lambdaTimeoutRe  = regexp.MustCompile(`(\S{36}) Task timed out after`)
+	lambdaDurationRe = regexp.MustCompile(`(Billed Duration|Init Duration|Duration|Memory Size|Max Memory Used|Status): ([0-9.]+|\w+)`)
+)
+
+// ParseInvocations builds invocation records from START, REPORT, INIT_START
+// and timeout lines. Only invocations with a REPORT line are returned, in
+// the order their REPORT lines appear.
+func ParseInvocations(events []TailEvent) []Invocation {
+	type pending struct {
+		start    TailEvent
+		cold     bool
+		timedOut bool
+	}
+	open := map[string]*pending{}
+	initSeen := map[string]bool{} // streams with an INIT_START awaiting its START
+	var out []Invocation
+
+	for _, e := range events {
+		msg := strings.TrimSpace(e.Message)
+		stream := e.LogGroup + "|" + e.LogStream
+		switch {
+		case strings.HasPrefix(msg, "INIT_START"):
+			initSeen[stream] = true
+		case lambdaStartRe.MatchString(msg):
+			id := lambdaStartRe.FindStringSubmatch(msg)[1]
+			open[id] = &pending{start: e, cold: initSeen[stream]}
+			delete(initSeen, stream)
+		case lambdaReportRe.MatchString(msg):
+			id := lambdaReportRe.FindStringSubmatch(msg)[1]
+			inv := parseReport(msg)
+			inv.RequestID = id
+			inv.LogGroup = e.LogGroup
+			inv.LogStream = e.LogStream
+			inv.Start = e.Timestamp.Add(-inv.Duration)
+			inv.Anchor = e
+			if p, ok := open[id]; ok {
+				inv.Start = p.start.Timestamp
+				inv.Anchor = p.start
+				inv.ColdStart = inv.ColdStart || p.cold
+				inv.TimedOut = inv.TimedOut || p.timedOut
+				delete(open, id)
+			}
+			out = append(out, inv)
+		default:
+			if m := lambdaTimeoutRe.FindStringSubmatch(msg); m != nil {
+				if p, ok := open[m[1]]; ok {
+					p.timedOut = true
+				}
+			}
+		}
+	}
+	return out
+}
+
+func parseReport(msg string) Invocation {
+	var inv Invocation
+	for _, m := range lambdaDurationRe.FindAllStringSubmatch(msg, -1) {
+		switch m[1] {
+		case "Duration":
+			inv.Duration = parseMillis(m[2])
+		case "Billed Duration":
+			inv.BilledDuration = parseMillis(m[2])
+		case "Init Duration":
+			inv.InitDuration = parseMillis(m[2])
+			inv.ColdStart = true
+		case "Memory Size":
+			inv.MemorySizeMB, _ = strconv.Atoi(m[2])
+		case "Max Memory Used":
+			inv.MaxMemoryUsedMB, _ = strconv.Atoi(m[2])
+		case "Status":
+			inv.TimedOut = m[2] == "timeout"
+		}
+	}
+	return inv
+}
+
+func parseMillis(v string) time.Duration {
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return 0
+	}
+	return time.Duration(f * float64(time.Millisecond))
+}
+
+// LambdaStats summarizes a set of invocations.
+type LambdaStats struct {
+	Count         int
+	ColdStarts    int
+	ColdStartRate float64 // percent
+	AvgInit       time.Duration
+	Timeouts      int
+	// Functions holds one summary per function, sorted by key. Durations
+	// and memory are only meaningful within a function.
+	Functions []FunctionStats
+	Slowest   []Invocation
+}
+
+// FunctionStats summarizes the invocations of one function.
+type FunctionStats struct {
+	Key          string
+	Count        int
+	P50          time.Duration
+	P90          time.Duration
+	P99          time.Duration
+	Max          time.Duration
+	ColdStarts   int
+	Timeouts     int
+	MemorySizeMB int
+	MaxMemoryMB  int
+	// MemoryHeadroom is the percentage of configured memory left unused by
+	// the most memory-hungry invocation.
+	MemoryHeadroom float64
+}
+
+// SummarizeInvocations computes the cold-start rate over every invocation,
+// duration percentiles and memory headroom per function keyed by the value
+// returned from key, and returns the n slowest invocations.
+func SummarizeInvocations(invs []Invocation, n int, key func(Invocation) string) LambdaStats {
+	stats := LambdaStats{Count: len(invs)}
+	if len(invs) == 0 {
+		return stats
+	}
+	functions := map[string]*FunctionStats{}
+	durations := map[string][]time.Duration{}
+	var initTotal time.Duration
+	for _, inv := range invs {
+		k := key(inv)
+		f, ok := functions[k]
+		if !ok {
+			f = &FunctionStats{Key: k}
+			functions[k] = f
+		}
+		f.Count++
+		durations[k] = append(durations[k], inv.Duration)
+		if inv.ColdStart {
+			stats.ColdStarts++
+			f.ColdStarts++
+			initTotal += inv.InitDuration
+		}
+		if inv.TimedOut {
+			stats.Timeouts++
+			f.Timeouts++
+		}
+		f.MemorySizeMB = max(f.MemorySizeMB, inv.MemorySizeMB)
+		f.MaxMemoryMB = max(f.MaxMemoryMB, inv.MaxMemoryUsedMB)
+	}
+	stats.ColdStartRate = 100 * float64(stats.ColdStarts) / float64(stats.Count)
+	if stats.ColdStarts > 0 {
+		stats.AvgInit = initTotal / time.Duration(stats.ColdStarts)
+	}
+	for k, f := range functions {
+		d := durations[k]
+		f.P50 = percentile(d, 50)
+		f.P90 = percentile(d, 90)
+		f.P99 = percentile(d, 99)
+		f.Max = percentile(d, 100)
+		if f.MemorySizeMB > 0 {
+			f.MemoryHeadroom = 100 * float64(f.MemorySizeMB-f.MaxMemoryMB) / float64(f.MemorySizeMB)
+		}
+		stats.Functions = append(stats.Functions, *f)
+	}
+	sort.Slice(stats.Functions, func(i, j int) bool { return stats.Functions[i].Key < stats.Functions[j].Key })
+
+	slowest := append([]Invocation(nil), invs...)
+	sort.SliceStable(slowest, func(i, j int) bool { return slowest[i].Duration > slowest[j].Duration })
+	if n > 0 && len(slowest) > n {
+		slowest = slowest[:n]
+	}
+	stats.Slowest = slowest
+	return stats
+}
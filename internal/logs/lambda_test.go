@@ -0,0 +1,87 @@
+package logs
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseInvocations(t *testing.T) {
+	base := time.Unix(1700000000, 0)
+	at := func(ms int) time.Time { return base.Add(time.Duration(ms) * time.Millisecond) }
+	const group = "/aws/lambda/orders"
+	events := []TailEvent{
+		{Timestamp: at(0), LogGroup: group, LogStream: "s1", Message: "INIT_START Runtime Version: python:3.12.v18\tRuntime Version ARN: arn:aws:lambda:us-east-1::runtime:abc"},
+		{Timestamp: at(300), LogGroup: group, LogStream: "s1", Message: "START RequestId: 11111111-1111-1111-1111-111111111111 Version: $LATEST"},
+		{Timestamp: at(400), LogGroup: group, LogStream: "s1", Message: "END RequestId: 11111111-1111-1111-1111-111111111111"},
+		{Timestamp: at(400), LogGroup: group, LogStream: "s1", Message: "REPORT RequestId: 11111111-1111-1111-1111-111111111111\tDuration: 100.50 ms\tBilled Duration: 101 ms\tMemory Size: 128 MB\tMax Memory Used: 96 MB\tInit Duration: 290.12 ms\t"},
+		{Timestamp: at(1000), LogGroup: group, LogStream: "s1", Message: "START RequestId: 22222222-2222-2222-2222-222222222222 Version: $LATEST"},
+		{Timestamp: at(4000), LogGroup: group, LogStream: "s1", Message: "2023-11-14T22:13:24.000Z 22222222-2222-2222-2222-222222222222 Task timed out after 3.00 seconds"},
+		{Timestamp: at(4000), LogGroup: group, LogStream: "s1", Message: "END RequestId: 22222222-2222-2222-2222-222222222222"},
+		{Timestamp: at(4000), LogGroup: group, LogStream: "s1", Message: "REPORT RequestId: 22222222-2222-2222-2222-222222222222\tDuration: 3000.00 ms\tBilled Duration: 3000 ms\tMemory Size: 128 MB\tMax Memory Used: 100 MB\t"},
+		{Timestamp: at(5000), LogGroup: group, LogStream: "s1", Message: "START RequestId: 33333333-3333-3333-3333-333333333333 Version: $LATEST"},
+	}
+
+	invs := ParseInvocations(events)
+	if len(invs) != 2 {
+		t.Fatalf("expected 2 completed invocations, got %d", len(invs))
+	}
+
+	cold := invs[0]
+	if !cold.ColdStart || cold.InitDuration != 290120*time.Microsecond {
+		t.Fatalf("expected cold start with init duration, got %+v", cold)
+	}
+	if cold.Duration != 100500*time.Microsecond || cold.BilledDuration != 101*time.Millisecond {
+		t.Fatalf("unexpected durations %+v", cold)
+	}
+	if cold.MemorySizeMB != 128 || cold.MaxMemoryUsedMB != 96 {
+		t.Fatalf("unexpected memory %+v", cold)
+	}
+	if cold.Anchor.Message != events[1].Message || !cold.Start.Equal(at(300)) {
+		t.Fatalf("invocation must be anchored at its START line, got %+v", cold.Anchor)
+	}
+
+	warm := invs[1]
+	if warm.ColdStart || !warm.TimedOut {
+		t.Fatalf("expected warm timed-out invocation, got %+v", warm)
+	}
+
+	stats := SummarizeInvocations(invs, 1, func(inv Invocation) string { return inv.LogGroup })
+	if stats.Count != 2 || stats.ColdStarts != 1 || stats.ColdStartRate != 50 || stats.Timeouts != 1 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+	if len(stats.Functions) != 1 {
+		t.Fatalf("expected one function, got %+v", stats.Functions)
+	}
+	if f := stats.Functions[0]; f.MaxMemoryMB != 100 || f.MemoryHeadroom < 21.8 || f.MemoryHeadroom > 21.9 {
+		t.Fatalf("unexpected memory headroom %+v", f)
+	}
+	if len(stats.Slowest) != 1 || stats.Slowest[0].RequestID != warm.RequestID {
+		t.Fatalf("unexpected slowest %+v", stats.Slowest)
+	}
+}
+
+func TestSummarizeInvocationsPerFunction(t *testing.T) {
+	invs := []Invocation{
+		{LogGroup: "/aws/lambda/small", Duration: 10 * time.Millisecond, MemorySizeMB: 128, MaxMemoryUsedMB: 120},
+		{LogGroup: "/aws/lambda/small", Duration: 30 * time.Millisecond, MemorySizeMB: 128, MaxMemoryUsedMB: 64},
+		{LogGroup: "/aws/lambda/big", Duration: 2 * time.Second, MemorySizeMB: 1024, MaxMemoryUsedMB: 200},
+	}
+	stats := SummarizeInvocations(invs, 0, func(inv Invocation) string { return inv.LogGroup })
+	if len(stats.Functions) != 2 {
+		t.Fatalf("expected two functions, got %+v", stats.Functions)
+	}
+	big, small := stats.Functions[0], stats.Functions[1]
+	if big.Key != "/aws/lambda/big" || small.Key != "/aws/lambda/small" {
+		t.Fatalf("functions must be sorted by key, got %q, %q", big.Key, small.Key)
+	}
+	// Memory of one function must not be compared with another's size.
+	if small.MemorySizeMB != 128 || small.MaxMemoryMB != 120 || small.MemoryHeadroom > 6.3 {
+		t.Fatalf("unexpected small function %+v", small)
+	}
+	if big.MemorySizeMB != 1024 || big.MaxMemoryMB != 200 || big.Count != 1 {
+		t.Fatalf("unexpected big function %+v", big)
+	}
+	if small.P50 != 10*time.Millisecond || small.Max != 30*time.Millisecond || big.P99 != 2*time.Second {
+		t.Fatalf("unexpected percentiles small=%+v big=%+v", small, big)
+	}
+}
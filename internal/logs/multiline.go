@@ -0,0 +1,84 @@
+package logs
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// DefaultJoinWindow is the largest gap between two lines of one stack trace.
+const DefaultJoinWindow = time.Second
+
+var (
+	// A Java exception line such as "java.lang.IllegalStateException: ...".
+	javaExceptionRe = regexp.MustCompile(`^[a-zA-Z_$][\w$]*(\.[\w$]+)+(Exception|Error|Throwable)(:|$)`)
+	// The last line of a Python traceback such as "ValueError: bad input".
+	pythonExceptionRe = regexp.MustCompile(`^[A-Za-z_][\w.]*(Error|Exception|Exit|Interrupt|Warning)(:|$)`)
+)
+
+// IsContinuation reports whether a line continues the event before it:
+// indented lines, "at ..." frames, "Caused by:", Python tracebacks, and
+// qualified Java exception names.
+func IsContinuation(line string) bool {
+	if line == "" {
+		return false
+	}
+	if line[0] == ' ' || line[0] == '\t' {
+		return strings.TrimSpace(line) != ""
+	}
+	switch {
+	case strings.HasPrefix(line, "at "),
+		strings.HasPrefix(line, "Caused by:"),
+		strings.HasPrefix(line, "Suppressed:"),
+		strings.HasPrefix(line, "Traceback (most recent call last)"),
+		strings.HasPrefix(line, "During handling of the above exception"),
+		strings.HasPrefix(line, "The above exception was the direct cause"):
+		return true
+	}
+	return javaExceptionRe.MatchString(line)
+}
+
+type streamKey struct {
+	profile, region, group, stream string
+}
+
+// JoinMultiline folds continuation lines into the event they continue, per
+// log stream. A line joins when it follows the previous line of its stream
+// within window and either is a continuation or ends a Python traceback.
+// Joined events keep the first event's ID and timestamp, hold every line in
+// Message and the originals in Parts. The input is not modified.
+func JoinMultiline(events []TailEvent, window time.Duration) []TailEvent {
+	out := make([]TailEvent, 0, len(events))
+	last := map[streamKey]int{} // index in out of each stream's open event
+	lastSeen := map[streamKey]time.Time{}
+	for _, e := range events {
+		key := streamKey{e.Profile, e.Region, e.LogGroup, e.LogStream}
+		line := strings.TrimRight(e.Message, "\r\n")
+		idx, open := last[key]
+		if open && e.Timestamp.Sub(lastSeen[key]) <= window && joins(out[idx], line) {
+			parent := &out[idx]
+			if parent.Parts == nil {
+				parent.Parts = []TailEvent{*parent}
+				parent.Message = strings.TrimRight(parent.Message, "\r\n")
+			}
+			parent.Parts = append(parent.Parts, e)
+			parent.Message += "\n" + line
+			lastSeen[key] = e.Timestamp
+			continue
+		}
+		last[key] = len(out)
+		lastSeen[key] = e.Timestamp
+		out = append(out, e)
+	}
+	return out
+}
+
+func joins(parent TailEvent, line string) bool {
+	if IsContinuation(line) {
+		return true
+	}
+	// The exception line closes a traceback right after its indented frames.
+	lastLine := parent.Message[strings.LastIndex(parent.Message, "\n")+1:]
+	return strings.Contains(parent.Message, "Traceback (most recent call last)") &&
+		strings.HasPrefix(lastLine, " ") && pythonExceptionRe.MatchString(line)
+}
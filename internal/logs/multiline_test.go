@@ -0,0 +1,73 @@
+package logs
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIsContinuation(t *testing.T) {
+	cases := map[string]bool{
+		"\tat com.demo.Worker.run(Worker.java:41)":            true,
+		"    at com.demo.Worker.run(Worker.java:41)":          true,
+		"at com.demo.Worker.run(Worker.java:41)":              true,
+		"Caused by: java.io.IOException: closed":              true,
+		"java.lang.IllegalStateException: insufficient stock": true,
+		"Traceback (most recent call last):":                  true,
+		`  File "app.py", line 3, in <module>`:                true,
+		"2024-05-01 10:00:00 ERROR failed to reserve stock":   false,
+		`{"level":"info","message":"at the store"}`:           false,
+		"ValueError: bad input":                               false,
+		"   ":                                                 false,
+		"":                                                    false,
+	}
+	for line, want := range cases {
+		if got := IsContinuation(line); got != want {
+			t.Errorf("%q: got %v want %v", line, got, want)
+		}
+	}
+}
+
+func TestJoinMultilinePerStream(t *testing.T) {
+	base := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	ev := func(id, stream string, ms int, msg string) TailEvent {
+		return TailEvent{ID: id, LogGroup: "/app", LogStream: stream, Timestamp: base.Add(time.Duration(ms) * time.Millisecond), Message: msg}
+	}
+	events := []TailEvent{
+		ev("1", "a", 0, "ERROR failed to reserve stock"),
+		ev("2", "b", 1, "INFO unrelated line from another stream"),
+		ev("3", "a", 2, "java.lang.IllegalStateException: insufficient stock"),
+		ev("4", "b", 2, "\tat com.demo.Other.run(Other.java:1)"),
+		ev("5", "a", 3, "\tat com.demo.Worker.run(Worker.java:41)"),
+		ev("6", "a", 4, "Caused by: java.sql.SQLException: pool exhausted"),
+		ev("7", "a", 5000, "\tat late.Frame(Late.java:1)"),
+		ev("8", "c", 10, "Traceback (most recent call last):"),
+		ev("9", "c", 11, `  File "app.py", line 3, in <module>`),
+		ev("10", "c", 12, "ValueError: bad input"),
+		ev("11", "c", 13, "ValueError: a new event"),
+	}
+	out := JoinMultiline(events, DefaultJoinWindow)
+
+	if len(out) != 5 {
+		t.Fatalf("expected 5 events, got %d: %+v", len(out), out)
+	}
+	java := out[0]
+	if java.ID != "1" || len(java.Parts) != 4 || !strings.HasSuffix(java.Message, "Caused by: java.sql.SQLException: pool exhausted") {
+		t.Fatalf("unexpected java event %+v", java)
+	}
+	if out[1].ID != "2" || len(out[1].Parts) != 2 {
+		t.Fatalf("stream b should join its own frame: %+v", out[1])
+	}
+	if out[2].ID != "7" || out[2].Parts != nil {
+		t.Fatalf("a frame after the join window should stay separate: %+v", out[2])
+	}
+	if out[3].ID != "8" || len(out[3].Parts) != 3 {
+		t.Fatalf("python traceback should end with its exception line: %+v", out[3])
+	}
+	if out[4].ID != "11" {
+		t.Fatalf("a second exception line should start a new event: %+v", out[4])
+	}
+	if events[0].Parts != nil || events[0].Message != "ERROR failed to reserve stock" {
+		t.Fatalf("input must not be modified")
+	}
+}
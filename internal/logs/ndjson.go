@@ -0,0 +1,47 @@
+package logs
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// ndjsonEvent is one line of the NDJSON format sacha exports.
+type ndjsonEvent struct {
+	Timestamp     time.Time  `json:"timestamp"`
+	LogGroup      string     `json:"logGroup"`
+	LogStream     string     `json:"logStream,omitempty"`
+	ID            string     `json:"id,omitempty"`
+	Message       string     `json:"message"`
+	IngestionTime *time.Time `json:"ingestionTime,omitempty"`
+	Region        string     `json:"region,omitempty"`
+	Profile       string     `json:"profile,omitempty"`
+}
+
+// WriteNDJSON writes events as newline-delimited JSON, one event per line.
+// FileSource reads the output back.
+func WriteNDJSON(w io.Writer, events []TailEvent) error {
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	for _, e := range events {
+		line := ndjsonEvent{
+			Timestamp: e.Timestamp,
+			LogGroup:  e.LogGroup,
+			LogStream: e.LogStream,
+			ID:        e.ID,
+			Message:   e.Message,
+			Region:    e.Region,
+			Profile:   e.Profile,
+		}
+		if !e.IngestionTime.IsZero() {
+			ingested := e.IngestionTime
+			line.IngestionTime = &ingested
+		}
+		if err := enc.Encode(line); err != nil {
+			return fmt.Errorf("write ndjson: %w", err)
+		}
+	}
+	return bw.Flush()
+}
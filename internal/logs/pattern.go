@@ -0,0 +1,40 @@
+package logs
+
+import "strings"
+
+// MatchPattern returns a matcher for the plain-text subset of CloudWatch
+// filter patterns: every quoted phrase or bare term must appear in the
+// message. Local files and the demo fake filter with it.
+func MatchPattern(pattern string) func(message string) bool {
+	terms := patternTerms(pattern)
+	return func(message string) bool {
+		for _, t := range terms {
+			if !strings.Contains(message, t) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// patternTerms splits a filter pattern into quoted phrases and bare terms.
+// A quote inside a phrase is escaped as \".
+func patternTerms(pattern string) []string {
+	var terms []string
+	rest := strings.TrimSpace(pattern)
+	for rest != "" {
+		if rest[0] == '"' {
+			end := 1
+			for end < len(rest) && !(rest[end] == '"' && rest[end-1] != '\\') {
+				end++
+			}
+			terms = append(terms, strings.ReplaceAll(rest[1:min(end, len(rest))], `\"`, `"`))
+			rest = strings.TrimSpace(rest[min(end+1, len(rest)):])
+			continue
+		}
+		term, after, _ := strings.Cut(rest, " ")
+		terms = append(terms, term)
+		rest = strings.TrimSpace(after)
+	}
+	return terms
+}
@@ -0,0 +1,23 @@
+package logs
+
+import "testing"
+
+func TestMatchPattern(t *testing.T) {
+	for _, tc := range []struct {
+		pattern, message string
+		want             bool
+	}{
+		{"", "anything", true},
+		{"ERROR", "level=ERROR id=1", true},
+		{"ERROR timeout", "ERROR: db timeout", true},
+		{"ERROR timeout", "ERROR: db refused", false},
+		{`"request id" 42`, "request id 42 done", true},
+		{`"request id"`, "request-id 42", false},
+		{`"say \"hi\""`, `they say "hi" twice`, true},
+		{`"unterminated phrase`, "an unterminated phrase", true},
+	} {
+		if got := MatchPattern(tc.pattern)(tc.message); got != tc.want {
+			t.Errorf("MatchPattern(%q)(%q) = %v, want %v", tc.pattern, tc.message, got, tc.want)
+		}
+	}
+}
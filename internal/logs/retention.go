@@ -0,0 +1,31 @@
+package logs
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+)
+
+// RetentionDays are the retention periods CloudWatch Logs accepts.
+var RetentionDays = []int32{1, 3, 5, 7, 14, 30, 60, 90, 120, 150, 180, 365, 400, 545, 731, 1096, 1827, 2192, 2557, 2922, 3288, 3653}
+
+// SetRetention sets how many days a log group keeps its events.
+func (c *Client) SetRetention(ctx context.Context, group string, days int32) error {
+	if group == "" {
+		return fmt.Errorf("set retention: log group is required")
+	}
+	if !slices.Contains(RetentionDays, days) {
+		return fmt.Errorf("set retention: %d days is not supported, use one of %v", days, RetentionDays)
+	}
+	_, err := c.api.PutRetentionPolicy(ctx, &cloudwatchlogs.PutRetentionPolicyInput{
+		LogGroupName:    aws.String(group),
+		RetentionInDays: aws.Int32(days),
+	})
+	if err != nil {
+		return fmt.Errorf("put retention policy: %w", err)
+	}
+	return nil
+}
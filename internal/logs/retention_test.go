@@ -0,0 +1,37 @@
+package logs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+)
+
+type fakeRetentionAPI struct {
+	CloudWatchLogsAPI
+	calls []*cloudwatchlogs.PutRetentionPolicyInput
+}
+
+func (f *fakeRetentionAPI) PutRetentionPolicy(ctx context.Context, params *cloudwatchlogs.PutRetentionPolicyInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.PutRetentionPolicyOutput, error) {
+	f.calls = append(f.calls, params)
+	return &cloudwatchlogs.PutRetentionPolicyOutput{}, nil
+}
+
+func TestSetRetention(t *testing.T) {
+	api := &fakeRetentionAPI{}
+	client := &Client{api: api}
+
+	if err := client.SetRetention(context.Background(), "/aws/lambda/a", 30); err != nil {
+		t.Fatalf("set retention: %v", err)
+	}
+	if len(api.calls) != 1 || aws.ToString(api.calls[0].LogGroupName) != "/aws/lambda/a" || aws.ToInt32(api.calls[0].RetentionInDays) != 30 {
+		t.Fatalf("unexpected calls %+v", api.calls)
+	}
+	if err := client.SetRetention(context.Background(), "/aws/lambda/a", 31); err == nil {
+		t.Fatalf("expected an error for an unsupported period")
+	}
+	if len(api.calls) != 1 {
+		t.Fatalf("invalid periods must not reach the API")
+	}
+}
@@ -7,3 +7,20 @@ func sortEvents(events []TailEvent) {
 		return events[i].Timestamp.Before(events[j].Timestamp)
 	})
 }
+
+// MergeEvents combines event batches into a single slice ordered by timestamp.
+// Events with equal timestamps keep the order of their batches.
+func MergeEvents(batches ...[]TailEvent) []TailEvent {
+	total := 0
+	for _, b := range batches {
+		total += len(b)
+	}
+	out := make([]TailEvent, 0, total)
+	for _, b := range batches {
+		out = append(out, b...)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].Timestamp.Before(out[j].Timestamp)
+	})
+	return out
+}
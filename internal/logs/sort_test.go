@@ -0,0 +1,30 @@
+package logs
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMergeEventsOrdersAcrossBatches(t *testing.T) {
+	base := time.Unix(1700000000, 0)
+	east := []TailEvent{
+		{Timestamp: base, Message: "east-1", Region: "us-east-1"},
+		{Timestamp: base.Add(2 * time.Second), Message: "east-2", Region: "us-east-1"},
+	}
+	west := []TailEvent{
+		{Timestamp: base, Message: "west-1", Region: "eu-west-1"},
+		{Timestamp: base.Add(time.Second), Message: "west-2", Region: "eu-west-1"},
+	}
+
+	got := MergeEvents(east, west)
+
+	want := []string{"east-1", "west-1", "west-2", "east-2"}
+	if len(got) != len(want) {
+		t.Fatalf("got %d events, want %d", len(got), len(want))
+	}
+	for i, msg := range want {
+		if got[i].Message != msg {
+			t.Fatalf("event %d: got %s want %s", i, got[i].Message, msg)
+		}
+	}
+}
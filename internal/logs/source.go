@@ -0,0 +1,57 @@
+package logs
+
+import (
+	"context"
+	"time"
+)
+
+// Source provides log groups and their events. Client reads them from
+// CloudWatch Logs; FileSource reads local log dumps. Operations only some
+// sources support have interfaces of their own below.
+type Source interface {
+	// ListLogGroups returns a page of log groups and the next token, if any.
+	ListLogGroups(ctx context.Context, nextToken *string) ([]LogGroup, *string, error)
+	// FetchEvents returns events at or after start, ordered by timestamp,
+	// and the position to resume from.
+	FetchEvents(ctx context.Context, groups []string, start time.Time) ([]TailEvent, time.Time, error)
+	// SearchEvents returns events between start and end matching a
+	// CloudWatch filter pattern, ordered by timestamp, and whether the
+	// search stopped at a limit before reading every match.
+	SearchEvents(ctx context.Context, groups []string, pattern string, start, end time.Time) ([]TailEvent, bool, error)
+}
+
+// QueryRunner runs Logs Insights queries and keeps saved query definitions.
+type QueryRunner interface {
+	ListQueryDefinitions(ctx context.Context) ([]QueryDefinition, error)
+	SaveQueryDefinition(ctx context.Context, def QueryDefinition) (string, error)
+	DeleteQueryDefinition(ctx context.Context, id string) error
+	RunQuery(ctx context.Context, groups []string, query string, start, end time.Time) (QueryResult, error)
+}
+
+// Exporter exports log groups to S3.
+type Exporter interface {
+	CreateExportTask(ctx context.Context, req ExportRequest) (string, error)
+	ListExportTasks(ctx context.Context) ([]ExportTask, error)
+	CancelExportTask(ctx context.Context, id string) error
+}
+
+// Writer puts events into log streams.
+type Writer interface {
+	ListLogStreams(ctx context.Context, group string, limit int) ([]LogStream, error)
+	PutEvents(ctx context.Context, group, stream string, messages []string, at time.Time) error
+}
+
+// RetentionSetter changes how long log groups keep their events.
+type RetentionSetter interface {
+	SetRetention(ctx context.Context, group string, days int32) error
+}
+
+var (
+	_ Source = (*Client)(nil)
+	_ Source = (*FileSource)(nil)
+
+	_ QueryRunner     = (*Client)(nil)
+	_ Exporter        = (*Client)(nil)
+	_ Writer          = (*Client)(nil)
+	_ RetentionSetter = (*Client)(nil)
+)
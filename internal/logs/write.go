@@ -0,0 +1,86 @@
+package logs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+)
+
+// LogStream is a stream within a log group.
+type LogStream struct {
+	Name           string
+	LastEventTime  time.Time
+	LastIngestTime time.Time
+}
+
+// ListLogStreams returns up to limit streams of a group, most recently
+// written first.
+func (c *Client) ListLogStreams(ctx context.Context, group string, limit int) ([]LogStream, error) {
+	out, err := c.api.DescribeLogStreams(ctx, &cloudwatchlogs.DescribeLogStreamsInput{
+		LogGroupName: aws.String(group),
+		OrderBy:      types.OrderByLastEventTime,
+		Descending:   aws.Bool(true),
+		Limit:        aws.Int32(int32(limit)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("describe log streams: %w", err)
+	}
+	streams := make([]LogStream, 0, len(out.LogStreams))
+	for _, s := range out.LogStreams {
+		stream := LogStream{Name: aws.ToString(s.LogStreamName)}
+		if s.LastEventTimestamp != nil {
+			stream.LastEventTime = time.UnixMilli(*s.LastEventTimestamp)
+		}
+		if s.LastIngestionTime != nil {
+			stream.LastIngestTime = time.UnixMilli(*s.LastIngestionTime)
+		}
+		streams = append(streams, stream)
+	}
+	return streams, nil
+}
+
+// PutEvents writes messages to a stream, all stamped with at, and creates
+// the stream when it does not exist yet.
+func (c *Client) PutEvents(ctx context.Context, group, stream string, messages []string, at time.Time) error {
+	if group == "" || stream == "" {
+		return fmt.Errorf("put log events: log group and stream are required")
+	}
+	if len(messages) == 0 {
+		return fmt.Errorf("put log events: nothing to send")
+	}
+	in := &cloudwatchlogs.PutLogEventsInput{
+		LogGroupName:  aws.String(group),
+		LogStreamName: aws.String(stream),
+	}
+	for _, msg := range messages {
+		in.LogEvents = append(in.LogEvents, types.InputLogEvent{
+			Message:   aws.String(msg),
+			Timestamp: aws.Int64(at.UnixMilli()),
+		})
+	}
+
+	out, err := c.api.PutLogEvents(ctx, in)
+	var notFound *types.ResourceNotFoundException
+	if errors.As(err, &notFound) {
+		if _, cerr := c.api.CreateLogStream(ctx, &cloudwatchlogs.CreateLogStreamInput{
+			LogGroupName:  aws.String(group),
+			LogStreamName: aws.String(stream),
+		}); cerr != nil {
+			return fmt.Errorf("create log stream: %w", cerr)
+		}
+		out, err = c.api.PutLogEvents(ctx, in)
+	}
+	if err != nil {
+		return fmt.Errorf("put log events: %w", err)
+	}
+	if r := out.RejectedLogEventsInfo; r != nil {
+		return fmt.Errorf("put log events: events rejected (too old before %d, too new from %d, expired before %d)",
+			aws.ToInt32(r.TooOldLogEventEndIndex), aws.ToInt32(r.TooNewLogEventStartIndex), aws.ToInt32(r.ExpiredLogEventEndIndex))
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package logs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+)
+
+type fakeWriteAPI struct {
+	CloudWatchLogsAPI
+
+	streams map[string]bool
+	puts    []*cloudwatchlogs.PutLogEventsInput
+}
+
+func (f *fakeWriteAPI) CreateLogStream(ctx context.Context, in *cloudwatchlogs.CreateLogStreamInput, _ ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.CreateLogStreamOutput, error) {
+	f.streams[aws.ToString(in.LogStreamName)] = true
+	return &cloudwatchlogs.CreateLogStreamOutput{}, nil
+}
+
+func (f *fakeWriteAPI) PutLogEvents(ctx context.Context, in *cloudwatchlogs.PutLogEventsInput, _ ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.PutLogEventsOutput, error) {
+	if !f.streams[aws.ToString(in.LogStreamName)] {
+		return nil, &types.ResourceNotFoundException{Message: aws.String("The specified log stream does not exist.")}
+	}
+	f.puts = append(f.puts, in)
+	return &cloudwatchlogs.PutLogEventsOutput{}, nil
+}
+
+func TestPutEventsCreatesMissingStream(t *testing.T) {
+	api := &fakeWriteAPI{streams: map[string]bool{}}
+	c := &Client{api: api}
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	if err := c.PutEvents(context.Background(), "/app", "test", []string{`{"level":"error"}`, "second"}, at); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	if !api.streams["test"] || len(api.puts) != 1 {
+		t.Fatalf("expected the stream to be created and one put, got %v %d", api.streams, len(api.puts))
+	}
+	events := api.puts[0].LogEvents
+	if len(events) != 2 || aws.ToInt64(events[0].Timestamp) != at.UnixMilli() || aws.ToString(events[1].Message) != "second" {
+		t.Fatalf("unexpected events %+v", events)
+	}
+
+	if err := c.PutEvents(context.Background(), "/app", "test", []string{"again"}, at); err != nil || len(api.puts) != 2 {
+		t.Fatalf("second put: %v", err)
+	}
+	if err := c.PutEvents(context.Background(), "/app", "test", nil, at); err == nil {
+		t.Fatalf("empty put must fail")
+	}
+}
@@ -0,0 +1,159 @@
+// Package theme defines the color schemes of sacha's views.
+package theme
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Built-in theme names accepted in the config file.
+const (
+	Dark         = "dark"
+	Light        = "light"
+	HighContrast = "high-contrast"
+	// NoColor is used instead of the configured theme when NO_COLOR is set.
+	NoColor = "no-color"
+)
+
+// Theme assigns colors to the roles views draw with. Colors are ANSI 256
+// numbers or hex values; empty means the terminal's default.
+type Theme struct {
+	Name string
+	// Title colors pane and overlay titles.
+	Title lipgloss.Color
+	// Border colors the border of the focused pane.
+	Border lipgloss.Color
+	// Cursor and CursorText color the line under the cursor.
+	Cursor     lipgloss.Color
+	CursorText lipgloss.Color
+	// Selected colors selected items, such as checked log groups.
+	Selected lipgloss.Color
+	// Dim colors secondary text: hints, labels, timestamps.
+	Dim lipgloss.Color
+	// Status colors status messages and active filters.
+	Status lipgloss.Color
+	// Warn colors warnings, such as growing ingestion lag.
+	Warn lipgloss.Color
+	// Mark colors bookmarks.
+	Mark lipgloss.Color
+	// Mono themes have no colors. Views fall back to bold, faint text and
+	// reverse video so the cursor stays visible.
+	Mono bool
+}
+
+var builtins = map[string]Theme{
+	Dark: {
+		Name: Dark, Title: "213", Border: "57", Cursor: "57", CursorText: "229",
+		Selected: "51", Dim: "241", Status: "44", Warn: "208", Mark: "212",
+	},
+	Light: {
+		Name: Light, Title: "90", Border: "25", Cursor: "25", CursorText: "231",
+		Selected: "24", Dim: "244", Status: "30", Warn: "166", Mark: "162",
+	},
+	HighContrast: {
+		Name: HighContrast, Title: "15", Border: "11", Cursor: "11", CursorText: "0",
+		Selected: "14", Dim: "7", Status: "10", Warn: "9", Mark: "13",
+	},
+	NoColor: {Name: NoColor, Mono: true},
+}
+
+// Names lists the built-in themes that can be configured.
+func Names() []string {
+	return []string{Dark, Light, HighContrast}
+}
+
+// Default is the theme used when none is configured.
+func Default() *Theme {
+	t := builtins[Dark]
+	return &t
+}
+
+var colorPattern = regexp.MustCompile(`^(#[0-9a-fA-F]{6}|#[0-9a-fA-F]{3}|[0-9]{1,3})$`)
+
+// Load returns the named built-in theme, empty meaning dark, with colors
+// overridden by role: "title", "border", "cursor", "cursorText",
+// "selected", "dim", "status", "warn" and "mark". noColor selects the
+// colorless theme and ignores both, as NO_COLOR asks.
+func Load(name string, colors map[string]string, noColor bool) (*Theme, error) {
+	if noColor {
+		t := builtins[NoColor]
+		return &t, nil
+	}
+	if name == "" {
+		name = Dark
+	}
+	t, ok := builtins[name]
+	if !ok || name == NoColor {
+		return nil, fmt.Errorf("theme: unknown theme %q, use one of %v", name, Names())
+	}
+	roles := t.roles()
+	var errs []error
+	for _, role := range slices.Sorted(maps.Keys(colors)) {
+		value := colors[role]
+		slot, ok := roles[role]
+		switch {
+		case !ok:
+			errs = append(errs, fmt.Errorf("unknown color role %q", role))
+		case !validColor(value):
+			errs = append(errs, fmt.Errorf("color %s: %q is neither an ANSI 256 number nor a hex color", role, value))
+		default:
+			*slot = lipgloss.Color(value)
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, fmt.Errorf("theme: %w", err)
+	}
+	return &t, nil
+}
+
+func (t *Theme) roles() map[string]*lipgloss.Color {
+	return map[string]*lipgloss.Color{
+		"title":      &t.Title,
+		"border":     &t.Border,
+		"cursor":     &t.Cursor,
+		"cursorText": &t.CursorText,
+		"selected":   &t.Selected,
+		"dim":        &t.Dim,
+		"status":     &t.Status,
+		"warn":       &t.Warn,
+		"mark":       &t.Mark,
+	}
+}
+
+func validColor(s string) bool {
+	if !colorPattern.MatchString(s) {
+		return false
+	}
+	if s[0] == '#' {
+		return true
+	}
+	n, err := strconv.Atoi(s)
+	return err == nil && n <= 255
+}
+
+// CursorStyle highlights the line under the cursor.
+func (t *Theme) CursorStyle() lipgloss.Style {
+	if t.Mono {
+		return lipgloss.NewStyle().Reverse(true)
+	}
+	return lipgloss.NewStyle().Foreground(t.CursorText).Background(t.Cursor)
+}
+
+// TitleStyle renders titles.
+func (t *Theme) TitleStyle() lipgloss.Style {
+	return lipgloss.NewStyle().Foreground(t.Title).Bold(true)
+}
+
+// DimStyle renders secondary text.
+func (t *Theme) DimStyle() lipgloss.Style {
+	if t.Mono {
+		return lipgloss.NewStyle().Faint(true)
+	}
+	return lipgloss.NewStyle().Foreground(t.Dim)
+}
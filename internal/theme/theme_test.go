@@ -0,0 +1,65 @@
+package theme
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadBuiltins(t *testing.T) {
+	for _, name := range append(Names(), "") {
+		th, err := Load(name, nil, false)
+		if err != nil {
+			t.Fatalf("load %q: %v", name, err)
+		}
+		if th.Mono || th.Title == "" || th.Cursor == "" {
+			t.Fatalf("theme %q is missing colors: %+v", name, th)
+		}
+	}
+	if th, _ := Load("", nil, false); th.Name != Dark {
+		t.Fatalf("default theme = %q, want dark", th.Name)
+	}
+}
+
+func TestLoadOverridesColors(t *testing.T) {
+	th, err := Load(Light, map[string]string{"title": "#d75fd7", "cursor": "33"}, false)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if th.Title != "#d75fd7" || th.Cursor != "33" {
+		t.Fatalf("overrides not applied: %+v", th)
+	}
+	if base, _ := Load(Light, nil, false); base.Title == "#d75fd7" {
+		t.Fatalf("overrides must not leak into the built-in theme")
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	if _, err := Load("solarized", nil, false); err == nil {
+		t.Fatalf("expected an error for an unknown theme")
+	}
+	if _, err := Load(NoColor, nil, false); err == nil {
+		t.Fatalf("no-color is chosen by NO_COLOR, not by name")
+	}
+	_, err := Load(Dark, map[string]string{"background": "0", "warn": "300", "mark": "red"}, false)
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	for _, want := range []string{`unknown color role "background"`, `color warn: "300"`, `color mark: "red"`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("error %q does not mention %q", err, want)
+		}
+	}
+}
+
+func TestNoColorWins(t *testing.T) {
+	th, err := Load("solarized", map[string]string{"title": "bad"}, true)
+	if err != nil {
+		t.Fatalf("NO_COLOR must ignore the configured theme: %v", err)
+	}
+	if !th.Mono || th.Title != "" {
+		t.Fatalf("expected the colorless theme, got %+v", th)
+	}
+	if !th.CursorStyle().GetReverse() {
+		t.Fatalf("the cursor must stay visible without colors")
+	}
+}
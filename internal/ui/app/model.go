@@ -3,28 +3,62 @@ package app
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	sdkaws "github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/rs/zerolog"
 	awsx "github.com/sachamama/sacha/internal/aws"
 	"github.com/sachamama/sacha/internal/config"
+	"github.com/sachamama/sacha/internal/history"
+	"github.com/sachamama/sacha/internal/keymap"
+	"github.com/sachamama/sacha/internal/theme"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 )
 
+// headerLines is the height of the app header above the service view.
+const headerLines = 1
+
+// KeyGroups are the default key bindings of the app. They are live on top
+// of the service's, so the two must not share keys.
+var KeyGroups = []keymap.Group{
+	{Title: "General", Actions: []keymap.Action{
+		{Name: "region", Help: "switch region", Keys: []string{"r"}},
+		{Name: "profile", Help: "switch profile", Keys: []string{"p"}},
+		{Name: "service", Help: "switch service", Keys: []string{"s"}},
+		{Name: "commands", Help: "command palette", Keys: []string{":", "ctrl+p"}},
+		{Name: "help", Help: "toggle help", Keys: []string{"?"}},
+		{Name: "quit", Help: "stop the tail, or quit", Keys: []string{"q"}},
+		{Name: "force-quit", Help: "quit, even from a prompt", Keys: []string{"ctrl+c"}},
+	}},
+}
+
 type Model struct {
 	loader   awsx.Loader
 	services map[string]awsx.Service
 
-	cfg     sdkaws.Config
-	runtime config.RuntimeConfig
+	cfg      sdkaws.Config
+	runtime  config.RuntimeConfig
+	settings *config.Config
+	history  *history.Store
+	keys     *keymap.Keymap
+	theme    *theme.Theme
 
 	service tea.Model
 
 	logger *zerolog.Logger
 
 	regionSelector  optionSelector
+	profileSelector optionSelector
 	serviceSelector optionSelector
+	palette         palette
+	login           ssoLogin
+	// declinedLogins holds the retries of the profiles whose SSO login was
+	// cancelled or failed; see requestLogin.
+	declinedLogins map[string]tea.Cmd
+	// profileNames maps the labels of the profile selector to profiles.
+	profileNames map[string]string
 
 	width    int
 	height   int
@@ -32,16 +66,24 @@ type Model struct {
 	status   string
 }
 
-func NewModel(loader awsx.Loader, services map[string]awsx.Service, runtime config.RuntimeConfig, cfg sdkaws.Config, logger *zerolog.Logger) (Model, error) {
+// NewModel builds the app around the active service. keys must hold the
+// bindings of the app and of every service; see KeyGroups.
+func NewModel(loader awsx.Loader, services map[string]awsx.Service, runtime config.RuntimeConfig, cfg sdkaws.Config, settings *config.Config, hist *history.Store, keys *keymap.Keymap, th *theme.Theme, logger *zerolog.Logger) (Model, error) {
 	m := Model{
 		loader:   loader,
 		services: services,
 		runtime:  runtime,
 		cfg:      cfg,
+		settings: settings,
+		history:  hist,
+		keys:     keys,
+		theme:    th,
 		logger:   logger,
 	}
 	m.regionSelector = newOptionSelector("Select Region", awsRegions)
+	m.profileSelector = newOptionSelector("Select Profile", nil)
 	m.serviceSelector = newOptionSelector("Select Service", serviceNames(services))
+	m.palette = newPalette()
 	if err := m.activateService(runtime.Service); err != nil {
 		return Model{}, err
 	}
@@ -65,29 +107,61 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.service, cmd = m.service.Update(msg)
 			return m, cmd
 		}
+	case awsx.SSOLoginMsg:
+		return m.requestLogin(msg)
+	case ssoLoginStartedMsg:
+		return m.loginStarted(msg)
+	case ssoLoginDoneMsg:
+		return m.loginDone(msg)
+	case tea.MouseMsg:
+		if m.login.active || m.regionSelector.active || m.profileSelector.active || m.serviceSelector.active || m.palette.active || m.showHelp || m.service == nil {
+			return m, nil
+		}
+		// Services lay out their view below the header line.
+		msg.Y -= headerLines
+		var cmd tea.Cmd
+		m.service, cmd = m.service.Update(msg)
+		return m, cmd
 	case tea.KeyMsg:
+		if m.login.active {
+			return m.handleLogin(msg)
+		}
+		if m.palette.active {
+			return m.handlePalette(msg)
+		}
 		if m.regionSelector.active {
 			return m.handleRegionSelector(msg)
 		}
+		if m.profileSelector.active {
+			return m.handleProfileSelector(msg)
+		}
 		if m.serviceSelector.active {
 			return m.handleServiceSelector(msg)
 		}
+		action := m.keys.Action(msg)
+		if action != "force-quit" && isCapturing(m.service) {
+			break
+		}
 
-		switch msg.String() {
-		case "ctrl+c":
+		switch action {
+		case "force-quit":
 			return m, tea.Quit
-		case "q":
+		case "quit":
 			if !isTailing(m.service) {
 				return m, tea.Quit
 			}
-		case "r":
+		case "region":
 			m.regionSelector.open(awsRegions, m.runtime.Region)
 			return m, m.regionSelector.input.Focus()
-		case "s":
+		case "profile":
+			return m, m.openProfiles()
+		case "service":
 			m.serviceSelector.open(serviceNames(m.services), m.runtime.Service)
 			return m, m.serviceSelector.input.Focus()
-		case "?":
+		case "help":
 			m.showHelp = !m.showHelp
+		case "commands":
+			return m, m.palette.open(m.paletteCommands())
 		}
 	}
 
@@ -102,14 +176,26 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (m Model) View() string {
 	header := fmt.Sprintf("profile: %s | region: %s | service: %s", emptyIf(m.runtime.Profile, "default"), emptyIf(m.runtime.Region, "sdk-default"), m.runtime.Service)
+	if m.runtime.Demo {
+		header += " | demo data"
+	}
+	if m.login.active {
+		return m.overlayView(header, m.login.View(minWidth(m.width, 90), m.theme))
+	}
 	if m.regionSelector.active {
-		return m.overlayView(header, m.regionSelector.View(minWidth(m.width, 60)))
+		return m.overlayView(header, m.regionSelector.View(minWidth(m.width, 60), m.theme))
+	}
+	if m.profileSelector.active {
+		return m.overlayView(header, m.profileSelector.View(minWidth(m.width, 90), m.theme))
 	}
 	if m.serviceSelector.active {
-		return m.overlayView(header, m.serviceSelector.View(minWidth(m.width, 60)))
+		return m.overlayView(header, m.serviceSelector.View(minWidth(m.width, 60), m.theme))
+	}
+	if m.palette.active {
+		return m.overlayView(header, m.palette.View(minWidth(m.width, 90), m.theme))
 	}
 	if m.showHelp {
-		return header + "\n" + helpView()
+		return header + "\n" + m.helpView()
 	}
 	body := ""
 	if m.service != nil {
@@ -117,7 +203,8 @@ func (m Model) View() string {
 	}
 	status := m.status
 	if status == "" {
-		status = "Keys: arrows/jk move, / search, space select, a select all, t tail, r region, s service, ? help, q stop tail, ctrl+c quit"
+		status = "Keys: " + m.keys.Hints("search", "search", "select", "select", "select-all", "select all", "tail", "tail",
+			"region", "region", "profile", "profile", "service", "service", "commands", "commands", "help", "help", "quit", "stop tail", "force-quit", "quit")
 	}
 	return fmt.Sprintf("%s\n%s\n%s", header, body, status)
 }
@@ -128,7 +215,15 @@ func (m *Model) activateService(name string) error {
 		return fmt.Errorf("unknown service %q", name)
 	}
 	model, err := svc.Init(context.Background(), m.cfg, awsx.ServiceOptions{
-		Logger: newLoggerAdapter(m.logger),
+		Logger:  newLoggerAdapter(m.logger),
+		Loader:  m.loader,
+		Profile: m.runtime.Profile,
+		Config:  m.settings,
+		History: m.history,
+		Files:   m.runtime.Files,
+		Demo:    m.runtime.Demo,
+		Keys:    m.keys,
+		Theme:   m.theme,
 	})
 	if err != nil {
 		return err
@@ -139,11 +234,26 @@ func (m *Model) activateService(name string) error {
 }
 
 func (m *Model) changeRegion(region string) (tea.Cmd, error) {
-	cfg, err := m.loader.Load(context.Background(), m.runtime.Profile, region)
+	return m.reload(m.runtime.Profile, region)
+}
+
+// changeProfile switches to another profile in the current region.
+func (m *Model) changeProfile(profile string) (tea.Cmd, error) {
+	return m.reload(profile, m.runtime.Region)
+}
+
+// reload loads the AWS config of profile and region and restarts the active
+// service with it.
+func (m *Model) reload(profile, region string) (tea.Cmd, error) {
+	cfg, err := m.loader.Load(context.Background(), profile, region)
 	if err != nil {
 		return nil, err
 	}
+	if region == "" {
+		region = cfg.Region
+	}
 	m.cfg = cfg
+	m.runtime.Profile = profile
 	m.runtime.Region = region
 	cmds := []tea.Cmd{}
 	if err := m.activateService(m.runtime.Service); err != nil {
@@ -160,8 +270,36 @@ func (m *Model) changeRegion(region string) (tea.Cmd, error) {
 	return tea.Batch(cmds...), nil
 }
 
-func helpView() string {
-	return "Navigation: arrows/j/k | Search: / | Select: space, a | Actions: t tail, r region, s service | Tail stop: q or esc | Quit app: ctrl+c"
+// helpView lists the effective key bindings, group by group.
+func (m Model) helpView() string {
+	width := m.width
+	if width <= 0 {
+		width = 80
+	}
+	var b strings.Builder
+	for _, g := range m.keys.Groups() {
+		fmt.Fprintln(&b, m.theme.TitleStyle().Render(g.Title))
+		line := ""
+		for _, a := range g.Actions {
+			hint := m.keys.Hint(a.Name, a.Help)
+			if hint == "" {
+				continue
+			}
+			if line != "" && lipgloss.Width(line)+3+lipgloss.Width(hint) > width-2 {
+				fmt.Fprintln(&b, "  "+line)
+				line = ""
+			}
+			if line != "" {
+				line += " • "
+			}
+			line += hint
+		}
+		if line != "" {
+			fmt.Fprintln(&b, "  "+line)
+		}
+	}
+	fmt.Fprint(&b, "Inside overlays and prompts: ↑/↓ move, enter choose, esc close. Keys can be changed in the config file under \"keys\".")
+	return b.String()
 }
 
 func emptyIf(value, fallback string) string {
@@ -227,6 +365,19 @@ func isTailing(m tea.Model) bool {
 	return false
 }
 
+// inputAware services report when they own the keyboard (text prompts),
+// so global shortcuts must not steal keystrokes.
+type inputAware interface {
+	Capturing() bool
+}
+
+func isCapturing(m tea.Model) bool {
+	if c, ok := m.(inputAware); ok {
+		return c.Capturing()
+	}
+	return false
+}
+
 // Runtime exposes the current runtime configuration after user interaction.
 func (m Model) Runtime() config.RuntimeConfig {
 	return m.runtime
@@ -245,23 +396,80 @@ func (m Model) handleRegionSelector(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// openProfiles lists the profiles of the shared AWS files with where their
+// credentials come from.
+func (m *Model) openProfiles() tea.Cmd {
+	profiles, err := m.loader.Profiles()
+	if err != nil {
+		m.status = err.Error()
+		return nil
+	}
+	if len(profiles) == 0 {
+		m.status = "no profiles in the shared AWS config or credentials files"
+		return nil
+	}
+	active := emptyIf(m.runtime.Profile, "default")
+	labels := make([]string, len(profiles))
+	current := ""
+	m.profileNames = map[string]string{}
+	for i, p := range profiles {
+		labels[i] = profileLabel(p)
+		m.profileNames[labels[i]] = p.Name
+		if p.Name == active {
+			current = labels[i]
+		}
+	}
+	m.profileSelector.open(labels, current)
+	return m.profileSelector.input.Focus()
+}
+
+func profileLabel(p awsx.Profile) string {
+	label := fmt.Sprintf("%-24s %-7s %s", p.Name, p.Type, p.Detail)
+	if p.Region != "" {
+		label += " (" + p.Region + ")"
+	}
+	return strings.TrimRight(label, " ")
+}
+
+func (m Model) handleProfileSelector(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	choice, cmd := m.profileSelector.update(msg)
+	if choice != "" {
+		changeCmd, err := m.changeProfile(m.profileNames[choice])
+		if err != nil {
+			m.status = err.Error()
+			return m, cmd
+		}
+		m.status = ""
+		return m, tea.Batch(cmd, changeCmd)
+	}
+	return m, cmd
+}
+
 func (m Model) handleServiceSelector(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	choice, cmd := m.serviceSelector.update(msg)
 	if choice != "" {
-		if err := m.activateService(choice); err != nil {
+		switchCmd, err := m.switchService(choice)
+		if err != nil {
 			m.status = err.Error()
 			return m, cmd
 		}
-		initCmds := []tea.Cmd{}
-		if m.service != nil {
-			initCmds = append(initCmds, m.service.Init())
-		}
-		if m.width > 0 && m.height > 0 {
-			initCmds = append(initCmds, func() tea.Msg {
-				return tea.WindowSizeMsg{Width: m.width, Height: m.height}
-			})
-		}
-		return m, tea.Batch(append(initCmds, cmd)...)
+		return m, tea.Batch(switchCmd, cmd)
 	}
 	return m, cmd
 }
+
+func (m *Model) switchService(name string) (tea.Cmd, error) {
+	if err := m.activateService(name); err != nil {
+		return nil, err
+	}
+	initCmds := []tea.Cmd{}
+	if m.service != nil {
+		initCmds = append(initCmds, m.service.Init())
+	}
+	if m.width > 0 && m.height > 0 {
+		initCmds = append(initCmds, func() tea.Msg {
+			return tea.WindowSizeMsg{Width: m.width, Height: m.height}
+		})
+	}
+	return tea.Batch(initCmds...), nil
+}
@@ -0,0 +1,236 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	awsx "github.com/sachamama/sacha/internal/aws"
+	"github.com/sachamama/sacha/internal/fuzzy"
+	"github.com/sachamama/sacha/internal/theme"
+)
+
+const paletteRows = 12
+
+// appCommands are the palette commands handled by the app itself.
+var appCommands = []awsx.Command{
+	{Name: "region", Description: "switch region", Args: "[region]"},
+	{Name: "profile", Description: "switch profile", Args: "[profile]"},
+	{Name: "service", Description: "switch service", Args: "[service]"},
+	{Name: "login", Description: "sign in to the SSO session of a profile", Args: "[profile]"},
+	{Name: "help", Description: "toggle help"},
+	{Name: "quit", Description: "quit sacha"},
+}
+
+// palette lists the commands of the app and the active service, fuzzy
+// matched against what is typed. Text after a command name is passed to it
+// as arguments, e.g. "region eu-west-1".
+type palette struct {
+	active   bool
+	input    textinput.Model
+	commands []awsx.Command
+	matches  []int
+	cursor   int
+}
+
+func newPalette() palette {
+	in := textinput.New()
+	in.Prompt = ": "
+	in.Placeholder = "command, e.g. region eu-west-1"
+	return palette{input: in}
+}
+
+func (p *palette) open(commands []awsx.Command) tea.Cmd {
+	p.active = true
+	p.commands = commands
+	p.input.SetValue("")
+	p.applyFilter()
+	return p.input.Focus()
+}
+
+func (p *palette) close() {
+	p.active = false
+	p.input.Blur()
+}
+
+// lookup returns the index of the command called name, or -1.
+func (p palette) lookup(name string) int {
+	for i, c := range p.commands {
+		if c.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// applyFilter ranks the commands against the input. Once a command name is
+// followed by a space, only that command is shown.
+func (p *palette) applyFilter() {
+	value := strings.TrimLeft(p.input.Value(), " ")
+	p.cursor = 0
+	if name, _, ok := strings.Cut(value, " "); ok {
+		if i := p.lookup(name); i >= 0 {
+			p.matches = []int{i}
+			return
+		}
+	}
+	items := make([]string, len(p.commands))
+	for i, c := range p.commands {
+		items[i] = c.Name + " " + c.Description
+	}
+	p.matches = p.matches[:0]
+	for _, r := range fuzzy.Rank(strings.TrimSpace(value), items) {
+		p.matches = append(p.matches, r.Index)
+	}
+}
+
+func (p *palette) complete(c awsx.Command) {
+	p.input.SetValue(c.Name + " ")
+	p.input.CursorEnd()
+	p.applyFilter()
+}
+
+// update returns the command to run once one is chosen.
+func (p *palette) update(msg tea.KeyMsg) (*awsx.CommandMsg, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		p.close()
+		return nil, nil
+	case "up", "ctrl+p":
+		if p.cursor > 0 {
+			p.cursor--
+		}
+		return nil, nil
+	case "down", "ctrl+n":
+		if p.cursor < len(p.matches)-1 {
+			p.cursor++
+		}
+		return nil, nil
+	case "tab":
+		if p.cursor < len(p.matches) {
+			p.complete(p.commands[p.matches[p.cursor]])
+		}
+		return nil, nil
+	case "enter":
+		name, args, _ := strings.Cut(strings.TrimSpace(p.input.Value()), " ")
+		i := p.lookup(name)
+		if i < 0 {
+			if p.cursor >= len(p.matches) {
+				return nil, nil
+			}
+			i, args = p.matches[p.cursor], ""
+		}
+		c := p.commands[i]
+		args = strings.TrimSpace(args)
+		if c.ArgsRequired() && args == "" {
+			p.complete(c)
+			return nil, nil
+		}
+		p.close()
+		return &awsx.CommandMsg{Name: c.Name, Args: args}, nil
+	}
+	var cmd tea.Cmd
+	prev := p.input.Value()
+	p.input, cmd = p.input.Update(msg)
+	if p.input.Value() != prev {
+		p.applyFilter()
+	}
+	return nil, cmd
+}
+
+func (p palette) View(width int, t *theme.Theme) string {
+	box := lipgloss.NewStyle().Width(width).Padding(1).Border(lipgloss.RoundedBorder()).BorderForeground(t.Border)
+	dim := t.DimStyle()
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n\n%s\n\n", t.TitleStyle().Render("Commands"), p.input.View())
+	if len(p.matches) == 0 {
+		fmt.Fprintln(&b, "No matches")
+	}
+	start := max(p.cursor-paletteRows+1, 0)
+	for i := start; i < len(p.matches) && i < start+paletteRows; i++ {
+		c := p.commands[p.matches[i]]
+		line := fmt.Sprintf("%-30s %s", strings.TrimSpace(c.Name+" "+c.Args), c.Description)
+		if i == p.cursor {
+			line = t.CursorStyle().Render("> " + line)
+		} else {
+			line = "  " + line
+		}
+		if c.Key != "" {
+			line += dim.Render(" [" + c.Key + "]")
+		}
+		fmt.Fprintln(&b, line)
+	}
+	fmt.Fprintln(&b, "\n↑/↓ to move, Tab to complete, Enter to run, Esc to cancel")
+	return box.Render(b.String())
+}
+
+// paletteCommands lists the app's commands followed by the service's.
+func (m Model) paletteCommands() []awsx.Command {
+	commands := make([]awsx.Command, len(appCommands))
+	for i, c := range appCommands {
+		c.Key = m.keys.Keys(c.Name)
+		commands[i] = c
+	}
+	if c, ok := m.service.(awsx.Commander); ok {
+		commands = append(commands, c.Commands()...)
+	}
+	return commands
+}
+
+func (m Model) handlePalette(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	run, cmd := m.palette.update(msg)
+	if run == nil {
+		return m, cmd
+	}
+	return m.runCommand(*run)
+}
+
+// runCommand runs an app command, or hands it to the service.
+func (m Model) runCommand(msg awsx.CommandMsg) (tea.Model, tea.Cmd) {
+	switch msg.Name {
+	case "region":
+		if msg.Args == "" {
+			m.regionSelector.open(awsRegions, m.runtime.Region)
+			return m, m.regionSelector.input.Focus()
+		}
+		cmd, err := m.changeRegion(msg.Args)
+		if err != nil {
+			m.status = err.Error()
+		}
+		return m, cmd
+	case "profile":
+		if msg.Args == "" {
+			return m, m.openProfiles()
+		}
+		cmd, err := m.changeProfile(msg.Args)
+		if err != nil {
+			m.status = err.Error()
+		}
+		return m, cmd
+	case "service":
+		if msg.Args == "" {
+			m.serviceSelector.open(serviceNames(m.services), m.runtime.Service)
+			return m, m.serviceSelector.input.Focus()
+		}
+		cmd, err := m.switchService(msg.Args)
+		if err != nil {
+			m.status = err.Error()
+		}
+		return m, cmd
+	case "login":
+		return m, m.loginCommand(emptyIf(msg.Args, m.runtime.Profile))
+	case "help":
+		m.showHelp = !m.showHelp
+		return m, nil
+	case "quit":
+		return m, tea.Quit
+	}
+	if m.service == nil {
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.service, cmd = m.service.Update(msg)
+	return m, cmd
+}
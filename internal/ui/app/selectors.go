@@ -9,6 +9,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	awsx "github.com/sachamama/sacha/internal/aws"
+	"github.com/sachamama/sacha/internal/theme"
 )
 
 // optionSelector is a lightweight searchable picker used for region/service selection.
@@ -95,20 +96,20 @@ func (s *optionSelector) current() string {
 	return s.filtered[s.cursor]
 }
 
-func (s optionSelector) View(width int) string {
-	box := lipgloss.NewStyle().Width(width).Padding(1).Border(lipgloss.RoundedBorder())
+func (s optionSelector) View(width int, t *theme.Theme) string {
+	box := lipgloss.NewStyle().Width(width).Padding(1).Border(lipgloss.RoundedBorder()).BorderForeground(t.Border)
 	var b strings.Builder
-	fmt.Fprintf(&b, "%s\n\n", s.title)
+	fmt.Fprintf(&b, "%s\n\n", t.TitleStyle().Render(s.title))
 	fmt.Fprintf(&b, "%s\n\n", s.input.View())
 	if len(s.filtered) == 0 {
 		fmt.Fprintln(&b, "No matches")
 	} else {
 		for i, item := range s.filtered {
-			cursor := "  "
 			if i == s.cursor {
-				cursor = "> "
+				fmt.Fprintln(&b, t.CursorStyle().Render("> "+item))
+				continue
 			}
-			fmt.Fprintf(&b, "%s%s\n", cursor, item)
+			fmt.Fprintf(&b, "  %s\n", item)
 		}
 	}
 	fmt.Fprintln(&b, "\n↑/↓ to move, type to filter, Enter to select, Esc to cancel")
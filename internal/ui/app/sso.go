@@ -0,0 +1,136 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	awsx "github.com/sachamama/sacha/internal/aws"
+	"github.com/sachamama/sacha/internal/theme"
+)
+
+// ssoLogin is the overlay of an SSO device login. It shows where to approve
+// the login while sacha waits for the token, then runs retry.
+type ssoLogin struct {
+	active  bool
+	profile string
+	// flow is nil until the device authorization has started.
+	flow  *awsx.SSOLogin
+	retry tea.Cmd
+	// ctx ends with the login; esc cancels it.
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+type ssoLoginStartedMsg struct {
+	profile string
+	flow    *awsx.SSOLogin
+	err     error
+}
+
+type ssoLoginDoneMsg struct {
+	flow *awsx.SSOLogin
+	err  error
+}
+
+// requestLogin handles a service's request for an SSO login. Requests for
+// a profile whose login was cancelled or failed are ignored until the user
+// asks for one with the login command, so failing polls do not reopen it.
+func (m Model) requestLogin(msg awsx.SSOLoginMsg) (tea.Model, tea.Cmd) {
+	if _, declined := m.declinedLogins[msg.Profile]; m.login.active || declined {
+		return m, nil
+	}
+	return m, m.startLogin(msg.Profile, msg.Retry)
+}
+
+// loginCommand signs in to profile on request. It retries what was waiting
+// for a login of that profile that was cancelled or failed.
+func (m *Model) loginCommand(profile string) tea.Cmd {
+	return m.startLogin(profile, m.declinedLogins[profile])
+}
+
+func (m *Model) startLogin(profile string, retry tea.Cmd) tea.Cmd {
+	if m.login.active {
+		m.login.cancel()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	m.login = ssoLogin{active: true, profile: profile, retry: retry, ctx: ctx, cancel: cancel}
+	delete(m.declinedLogins, profile)
+	loader := m.loader
+	return func() tea.Msg {
+		flow, err := loader.StartSSOLogin(ctx, profile)
+		return ssoLoginStartedMsg{profile: profile, flow: flow, err: err}
+	}
+}
+
+func (m Model) loginStarted(msg ssoLoginStartedMsg) (tea.Model, tea.Cmd) {
+	if !m.login.active || m.login.profile != msg.profile || m.login.flow != nil {
+		return m, nil
+	}
+	if msg.err != nil {
+		return m.endLogin(msg.err)
+	}
+	m.login.flow = msg.flow
+	flow, ctx := msg.flow, m.login.ctx
+	return m, func() tea.Msg {
+		return ssoLoginDoneMsg{flow: flow, err: flow.Wait(ctx)}
+	}
+}
+
+func (m Model) loginDone(msg ssoLoginDoneMsg) (tea.Model, tea.Cmd) {
+	if !m.login.active || m.login.flow != msg.flow {
+		return m, nil
+	}
+	return m.endLogin(msg.err)
+}
+
+// endLogin closes the overlay and retries what needed the login if it
+// succeeded.
+func (m Model) endLogin(err error) (tea.Model, tea.Cmd) {
+	login := m.login
+	login.cancel()
+	m.login = ssoLogin{}
+	if err != nil {
+		if m.declinedLogins == nil {
+			m.declinedLogins = map[string]tea.Cmd{}
+		}
+		m.declinedLogins[login.profile] = login.retry
+		if errors.Is(err, context.Canceled) {
+			m.status = "SSO login cancelled; run the login command to sign in"
+		} else {
+			m.status = err.Error()
+		}
+		return m, nil
+	}
+	m.status = "signed in to " + login.flow.Session.StartURL
+	return m, login.retry
+}
+
+func (m Model) handleLogin(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case m.keys.Matches(msg, "force-quit"):
+		m.login.cancel()
+		return m, tea.Quit
+	case msg.String() == "esc":
+		return m.endLogin(context.Canceled)
+	}
+	return m, nil
+}
+
+func (l ssoLogin) View(width int, t *theme.Theme) string {
+	box := lipgloss.NewStyle().Width(width).Padding(1).Border(lipgloss.RoundedBorder()).BorderForeground(t.Border)
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n\n", t.TitleStyle().Render("SSO login: "+emptyIf(l.profile, "default")))
+	if l.flow == nil {
+		fmt.Fprintln(&b, "Starting the device authorization...")
+	} else {
+		fmt.Fprintf(&b, "Open this page in a browser and approve the login:\n\n  %s\n\n", l.flow.URL)
+		fmt.Fprintf(&b, "Check that it shows the code %s\n\n", t.CursorStyle().Render(" "+l.flow.Code+" "))
+		fmt.Fprintln(&b, t.DimStyle().Render("Waiting for approval; the token is saved to the AWS SSO cache."))
+	}
+	fmt.Fprint(&b, "\nEsc to cancel")
+	return box.Render(b.String())
+}
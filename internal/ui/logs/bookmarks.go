@@ -0,0 +1,265 @@
+package logs
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/sachamama/sacha/internal/logs"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const bookmarkRows = 10
+
+// bookmarksOverlay lists the marked events. The note prompt is shared by
+// new marks and by editing a mark from the list.
+type bookmarksOverlay struct {
+	active  bool
+	cursor  int
+	noting  bool
+	noteKey string
+	input   textinput.Model
+}
+
+type timelineWrittenMsg struct {
+	path  string
+	count int
+	err   error
+}
+
+func newBookmarksOverlay() bookmarksOverlay {
+	in := textinput.New()
+	in.Placeholder = "optional note, enter to save"
+	in.Prompt = "note: "
+	in.CharLimit = 500
+	return bookmarksOverlay{input: in}
+}
+
+// markIndex returns the index of the bookmark on e, or -1.
+func (m Model) markIndex(e logs.TailEvent) int {
+	key := eventKey(e)
+	for i, mark := range m.marks {
+		if eventKey(mark.Event) == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// toggleMark bookmarks the event under the cursor and asks for a note, or
+// removes its bookmark.
+func (m *Model) toggleMark() tea.Cmd {
+	e, ok := m.cursorEvent()
+	if !ok {
+		return nil
+	}
+	if i := m.markIndex(e); i >= 0 {
+		m.marks = append(m.marks[:i], m.marks[i+1:]...)
+		m.statusLine = "bookmark removed"
+		m.refreshTail()
+		return nil
+	}
+	// Pin the cursor so the marked line stays put while new events arrive.
+	m.follow = false
+	m.cursorKey = eventKey(e)
+	m.marks = append(m.marks, logs.Bookmark{Event: e})
+	logs.SortBookmarks(m.marks)
+	m.statusLine = fmt.Sprintf("bookmarked (%d)", len(m.marks))
+	m.refreshTail()
+	return m.editNote(e)
+}
+
+func (m *Model) editNote(e logs.TailEvent) tea.Cmd {
+	b := &m.bookmarks
+	i := m.markIndex(e)
+	if i < 0 {
+		return nil
+	}
+	b.noting = true
+	b.noteKey = eventKey(e)
+	b.input.SetValue(m.marks[i].Note)
+	b.input.CursorEnd()
+	return b.input.Focus()
+}
+
+func (m Model) updateNote(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	b := &m.bookmarks
+	switch msg.Type {
+	case tea.KeyEscape, tea.KeyEnter:
+		b.noting = false
+		b.input.Blur()
+		if msg.Type == tea.KeyEnter {
+			for i := range m.marks {
+				if eventKey(m.marks[i].Event) == b.noteKey {
+					m.marks[i].Note = strings.TrimSpace(b.input.Value())
+				}
+			}
+		}
+		return m, nil
+	}
+	var cmd tea.Cmd
+	b.input, cmd = b.input.Update(msg)
+	return m, cmd
+}
+
+func (m *Model) openBookmarks() {
+	if len(m.marks) == 0 {
+		m.statusLine = "no bookmarks" + m.hint("mark", "marks the event under the cursor")
+		return
+	}
+	m.bookmarks.active = true
+	m.bookmarks.cursor = min(m.bookmarks.cursor, len(m.marks)-1)
+}
+
+func (m Model) updateBookmarks(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	b := &m.bookmarks
+	switch msg.String() {
+	case "esc", "q", "B":
+		b.active = false
+	case "up", "k":
+		if b.cursor > 0 {
+			b.cursor--
+		}
+	case "down", "j":
+		if b.cursor < len(m.marks)-1 {
+			b.cursor++
+		}
+	case "enter":
+		if b.cursor < len(m.marks) {
+			b.active = false
+			m.jumpToMark(m.marks[b.cursor])
+		}
+	case "e":
+		if b.cursor < len(m.marks) {
+			return m, m.editNote(m.marks[b.cursor].Event)
+		}
+	case "d":
+		if b.cursor < len(m.marks) {
+			m.marks = append(m.marks[:b.cursor], m.marks[b.cursor+1:]...)
+			if b.cursor >= len(m.marks) && b.cursor > 0 {
+				b.cursor--
+			}
+			if len(m.marks) == 0 {
+				b.active = false
+			}
+			m.refreshTail()
+		}
+	case "x":
+		b.active = false
+		return m, m.openSourcePrompt(promptTimeline)
+	}
+	return m, nil
+}
+
+// jumpToMark moves the tail cursor to a bookmark still in the buffer.
+func (m *Model) jumpToMark(mark logs.Bookmark) {
+	m.jumpTo(mark.Event)
+	for _, e := range m.visibleEvents() {
+		if eventKey(e) == m.cursorKey {
+			return
+		}
+	}
+	m.follow = true
+	m.cursorKey = ""
+	m.statusLine = "bookmark is no longer in the tail buffer"
+	m.refreshTail()
+}
+
+// stepMark moves the cursor to the next (delta 1) or previous (delta -1)
+// bookmarked event on screen, wrapping around.
+func (m *Model) stepMark(delta int) {
+	visible := m.visibleEvents()
+	var idx []int
+	for i, e := range visible {
+		if m.markIndex(e) >= 0 {
+			idx = append(idx, i)
+		}
+	}
+	if len(idx) == 0 {
+		m.statusLine = "no bookmarks in the tail"
+		return
+	}
+	cur := m.cursorIndex(visible)
+	var pick int
+	if delta > 0 {
+		pick = 0
+		for n, i := range idx {
+			if i > cur {
+				pick = n
+				break
+			}
+		}
+	} else {
+		pick = len(idx) - 1
+		for n := len(idx) - 1; n >= 0; n-- {
+			if idx[n] < cur {
+				pick = n
+				break
+			}
+		}
+	}
+	m.follow = false
+	m.cursorKey = eventKey(visible[idx[pick]])
+	m.statusLine = fmt.Sprintf("bookmark %d/%d", pick+1, len(idx))
+	if note := m.marks[m.markIndex(visible[idx[pick]])].Note; note != "" {
+		m.statusLine += ": " + note
+	}
+	m.refreshTail()
+}
+
+// writeTimelineCmd exports the bookmarks with their context in the tail
+// buffer as a Markdown incident timeline.
+func (m Model) writeTimelineCmd(path string) tea.Cmd {
+	marks := append([]logs.Bookmark(nil), m.marks...)
+	events := m.events
+	return func() tea.Msg {
+		f, err := os.Create(path)
+		if err != nil {
+			return timelineWrittenMsg{path: path, err: err}
+		}
+		err = logs.WriteTimeline(f, marks, events, logs.DefaultTimelineContext, time.Now())
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		return timelineWrittenMsg{path: path, count: len(marks), err: err}
+	}
+}
+
+func (m *Model) timelineWritten(msg timelineWrittenMsg) {
+	if msg.err != nil {
+		m.statusLine = fmt.Sprintf("timeline %s: %v", msg.path, msg.err)
+		return
+	}
+	m.statusLine = fmt.Sprintf("wrote %d bookmarks to %s", msg.count, msg.path)
+}
+
+func (m Model) renderBookmarks() string {
+	var b strings.Builder
+	fmt.Fprintln(&b, m.styles.title.Render(fmt.Sprintf("Bookmarks (%d)", len(m.marks))))
+	if m.bookmarks.noting {
+		fmt.Fprintln(&b, m.bookmarks.input.View())
+	} else {
+		fmt.Fprintln(&b, m.styles.dim.Render("enter jump, e note, d delete, x export timeline, esc close"))
+	}
+	now := time.Now()
+	start := 0
+	if m.bookmarks.cursor >= bookmarkRows {
+		start = m.bookmarks.cursor - bookmarkRows + 1
+	}
+	for i := start; i < len(m.marks) && i < start+bookmarkRows; i++ {
+		mark := m.marks[i]
+		first, _, _ := strings.Cut(strings.TrimSpace(mark.Event.Message), "\n")
+		line := fmt.Sprintf("%s | %s | %s", m.times.format(mark.Event.Timestamp, now), mark.Event.LogGroup, first)
+		if i == m.bookmarks.cursor {
+			line = m.styles.cursor.Render(line)
+		}
+		fmt.Fprintln(&b, line)
+		if mark.Note != "" {
+			fmt.Fprintln(&b, "  "+m.styles.status.Render(mark.Note))
+		}
+	}
+	return b.String()
+}
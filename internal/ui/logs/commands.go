@@ -0,0 +1,130 @@
+package logs
+
+import (
+	"strings"
+
+	awsx "github.com/sachamama/sacha/internal/aws"
+	"github.com/sachamama/sacha/internal/history"
+	"github.com/sachamama/sacha/internal/logs"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// commandArgs are the arguments palette commands take in place of what
+// their key prompts for: "<x>" when required, "[x]" when optional.
+var commandArgs = map[string]string{
+	"tail":          "[group...]",
+	"search":        "[text]",
+	"add-region":    "<region>",
+	"add-profile":   "<profile [region]>",
+	"open":          "[path]",
+	"export-buffer": "[file]",
+	"filter":        "[text or $.field=value]",
+	"fields":        "[$.path]",
+}
+
+// movements are bound actions that make no sense in the palette.
+var movements = map[string]bool{
+	"up": true, "down": true, "select": true, "expand": true,
+	"tail-up": true, "tail-down": true, "page-up": true, "page-down": true,
+	"scroll-left": true, "scroll-right": true,
+}
+
+// paletteOnly are commands without a key of their own.
+var paletteOnly = []awsx.Command{
+	{Name: "retention", Description: "set the retention of the log group under the cursor", Args: "<days>"},
+	{Name: "timeline", Description: "export bookmarks as a Markdown incident timeline", Args: "[file]"},
+}
+
+// Commands lists the palette commands of the service: its bound actions
+// with their effective keys, and the commands that have no key.
+func (m Model) Commands() []awsx.Command {
+	var out []awsx.Command
+	for _, g := range KeyGroups {
+		for _, a := range g.Actions {
+			if movements[a.Name] {
+				continue
+			}
+			out = append(out, awsx.Command{Name: a.Name, Description: a.Help, Key: m.keys.Keys(a.Name), Args: commandArgs[a.Name]})
+		}
+	}
+	return append(out, paletteOnly...)
+}
+
+// runCommand runs a palette command. Without arguments it does what its key
+// does. Arguments stand in for what the key would prompt for; commands that
+// need a tail do nothing without one, like their keys.
+func (m Model) runCommand(msg awsx.CommandMsg) (tea.Model, tea.Cmd) {
+	args := strings.TrimSpace(msg.Args)
+	switch {
+	case msg.Name == "retention":
+		return m.setRetention(args)
+	case msg.Name == "timeline":
+		if len(m.marks) == 0 {
+			m.statusLine = "no bookmarks to export"
+			return m, nil
+		}
+		if args == "" {
+			return m, m.openSourcePrompt(promptTimeline)
+		}
+		return m, m.writeTimelineCmd(args)
+	case args == "":
+	case msg.Name == "tail":
+		return m.tailNamed(strings.Fields(args))
+	case msg.Name == "search":
+		m.search.SetValue(args)
+		m.cursor = 0
+		return m, m.remember(history.KindGroupSearch, args, nil)
+	case msg.Name == "add-region":
+		return m.submitPrompt(promptRegion, args)
+	case msg.Name == "add-profile":
+		return m.submitPrompt(promptProfile, args)
+	case msg.Name == "open":
+		return m.submitPrompt(promptFile, args)
+	case msg.Name == "export-buffer" && m.tailing:
+		return m.submitPrompt(promptExport, args)
+	case msg.Name == "filter" && m.tailing:
+		m.filterInput.SetValue(args)
+		return m.updateFilterPrompt(tea.KeyMsg{Type: tea.KeyEnter})
+	case msg.Name == "fields" && m.tailing:
+		path, err := logs.ParseFieldPath(args)
+		if err != nil {
+			m.statusLine = err.Error()
+			return m, nil
+		}
+		m.stats.active = true
+		m.stats.path = path
+		m.stats.input.SetValue(args)
+		m.stats.cursor = 0
+		return m, nil
+	}
+	return m.runAction(msg.Name)
+}
+
+// submitPrompt answers a source prompt as if value had been typed.
+func (m Model) submitPrompt(kind sourcePrompt, value string) (tea.Model, tea.Cmd) {
+	m.openSourcePrompt(kind)
+	m.sourceInput.SetValue(value)
+	return m.updateSourcePrompt(tea.KeyMsg{Type: tea.KeyEnter})
+}
+
+// tailNamed selects exactly the named log groups, in every source that has
+// them, and tails them.
+func (m Model) tailNamed(names []string) (tea.Model, tea.Cmd) {
+	selected := map[groupRef]bool{}
+	for _, name := range names {
+		found := false
+		for _, g := range m.logGroups {
+			if g.Name == name {
+				selected[refOf(g)] = true
+				found = true
+			}
+		}
+		if !found {
+			m.statusLine = "no log group named " + name
+			return m, nil
+		}
+	}
+	m.selected = selected
+	return m.runAction("tail")
+}
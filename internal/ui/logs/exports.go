@@ -0,0 +1,360 @@
+package logs
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sachamama/sacha/internal/logs"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// exportRefresh is how often the task list reloads while the panel is open.
+	exportRefresh = 5 * time.Second
+	exportRows    = 15
+)
+
+type exportMode int
+
+const (
+	exportList exportMode = iota
+	exportForm
+	exportConfirmCancel
+)
+
+// exportsPanel lists the export-to-S3 tasks of one source and creates new
+// ones.
+type exportsPanel struct {
+	active bool
+	// source owns the group under the cursor when the panel opened, or is
+	// the primary source without groups.
+	source  string
+	mode    exportMode
+	loading bool
+	tasks   []logs.ExportTask
+	cursor  int
+	gen     int // invalidates refresh ticks from an earlier opening
+
+	fields []textinput.Model // group, bucket, prefix, from, to
+	focus  int
+}
+
+type exportTasksLoadedMsg struct {
+	source string
+	tasks  []logs.ExportTask
+	err    error
+}
+
+type exportChangedMsg struct {
+	action string
+	err    error
+}
+
+type exportTickMsg struct {
+	gen int
+}
+
+func newExportsPanel() exportsPanel {
+	prompts := []struct{ prompt, placeholder string }{
+		{"log group: ", "/aws/lambda/orders"},
+		{"bucket: ", "my-log-archive"},
+		{"prefix: ", "optional key prefix"},
+		{"from: ", "24h, 7d, 2024-05-01 or RFC3339"},
+		{"to: ", "now, or 2024-05 for the end of May"},
+	}
+	fields := make([]textinput.Model, len(prompts))
+	for i, p := range prompts {
+		in := textinput.New()
+		in.Prompt = p.prompt
+		in.Placeholder = p.placeholder
+		fields[i] = in
+	}
+	return exportsPanel{fields: fields}
+}
+
+// openExports shows the tasks of the source owning the group under the
+// cursor.
+func (m *Model) openExports() tea.Cmd {
+	source := m.primary
+	if groups := m.filteredGroups(); m.cursor < len(groups) {
+		source = refOf(groups[m.cursor]).source
+	}
+	if _, ok := m.sources[source].client.(logs.Exporter); !ok {
+		m.statusLine = unsupported("exports").Error()
+		return nil
+	}
+	e := &m.exports
+	if e.source != source {
+		e.tasks = nil
+		e.cursor = 0
+	}
+	e.source = source
+	e.active = true
+	e.mode = exportList
+	e.loading = true
+	e.gen++
+	return tea.Batch(m.listExportsCmd(), e.tick())
+}
+
+func (e exportsPanel) tick() tea.Cmd {
+	gen := e.gen
+	return tea.Tick(exportRefresh, func(time.Time) tea.Msg { return exportTickMsg{gen: gen} })
+}
+
+// exporter returns the source of the panel if it exports to S3.
+func (m Model) exporter() (logs.Exporter, error) {
+	e, ok := m.sources[m.exports.source].client.(logs.Exporter)
+	if !ok {
+		return nil, unsupported("exports")
+	}
+	return e, nil
+}
+
+func (m Model) listExportsCmd() tea.Cmd {
+	client, err := m.exporter()
+	source := m.exports.source
+	return func() tea.Msg {
+		if err != nil {
+			return exportTasksLoadedMsg{source: source, err: err}
+		}
+		tasks, err := client.ListExportTasks(context.Background())
+		return exportTasksLoadedMsg{source: source, tasks: tasks, err: err}
+	}
+}
+
+func (m Model) createExportCmd(req logs.ExportRequest) tea.Cmd {
+	client, err := m.exporter()
+	return func() tea.Msg {
+		if err != nil {
+			return exportChangedMsg{err: err}
+		}
+		id, err := client.CreateExportTask(context.Background(), req)
+		return exportChangedMsg{action: "started " + id, err: err}
+	}
+}
+
+func (m Model) cancelExportCmd(task logs.ExportTask) tea.Cmd {
+	client, err := m.exporter()
+	return func() tea.Msg {
+		if err != nil {
+			return exportChangedMsg{err: err}
+		}
+		err := client.CancelExportTask(context.Background(), task.ID)
+		return exportChangedMsg{action: "cancelled " + task.ID, err: err}
+	}
+}
+
+// updateExportsMsg handles async results and refresh ticks for the panel.
+func (m *Model) updateExportsMsg(msg tea.Msg) tea.Cmd {
+	e := &m.exports
+	switch msg := msg.(type) {
+	case exportTasksLoadedMsg:
+		if msg.source != e.source {
+			return nil
+		}
+		e.loading = false
+		if msg.err != nil {
+			m.statusLine = msg.err.Error()
+			return nil
+		}
+		e.tasks = msg.tasks
+		if e.cursor >= len(e.tasks) {
+			e.cursor = max(len(e.tasks)-1, 0)
+		}
+	case exportChangedMsg:
+		if msg.err != nil {
+			m.statusLine = msg.err.Error()
+			return nil
+		}
+		m.statusLine = "export " + msg.action
+		e.loading = true
+		return m.listExportsCmd()
+	case exportTickMsg:
+		if !e.active || msg.gen != e.gen {
+			return nil
+		}
+		return tea.Batch(m.listExportsCmd(), e.tick())
+	}
+	return nil
+}
+
+func (m Model) updateExports(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	e := &m.exports
+	switch e.mode {
+	case exportForm:
+		return m.updateExportForm(msg)
+	case exportConfirmCancel:
+		e.mode = exportList
+		if msg.String() == "y" && e.cursor < len(e.tasks) {
+			return m, m.cancelExportCmd(e.tasks[e.cursor])
+		}
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "esc", "q":
+		e.active = false
+	case "up", "k":
+		if e.cursor > 0 {
+			e.cursor--
+		}
+	case "down", "j":
+		if e.cursor < len(e.tasks)-1 {
+			e.cursor++
+		}
+	case "g":
+		e.loading = true
+		return m, m.listExportsCmd()
+	case "n":
+		return m, m.openExportForm()
+	case "c":
+		if e.cursor < len(e.tasks) && e.tasks[e.cursor].Active() {
+			e.mode = exportConfirmCancel
+		}
+	}
+	return m, nil
+}
+
+// openExportForm prefills the group under the cursor if it belongs to the
+// source of the panel, and the destination of the most recent task.
+func (m *Model) openExportForm() tea.Cmd {
+	e := &m.exports
+	group := ""
+	if groups := m.filteredGroups(); m.cursor < len(groups) {
+		if ref := refOf(groups[m.cursor]); ref.source == e.source {
+			group = ref.name
+		}
+	}
+	bucket, prefix := "", ""
+	if len(e.tasks) > 0 {
+		bucket, prefix = e.tasks[0].Bucket, e.tasks[0].Prefix
+	}
+	for i, v := range []string{group, bucket, prefix, "24h", "now"} {
+		e.fields[i].SetValue(v)
+		e.fields[i].CursorEnd()
+	}
+	e.mode = exportForm
+	e.focus = 0
+	if group != "" {
+		e.focus = 1
+	}
+	return e.focusField()
+}
+
+func (e *exportsPanel) focusField() tea.Cmd {
+	for i := range e.fields {
+		e.fields[i].Blur()
+	}
+	return e.fields[e.focus].Focus()
+}
+
+func (m Model) updateExportForm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	e := &m.exports
+	switch msg.String() {
+	case "esc":
+		e.mode = exportList
+		return m, nil
+	case "tab", "down":
+		e.focus = (e.focus + 1) % len(e.fields)
+		return m, e.focusField()
+	case "shift+tab", "up":
+		e.focus = (e.focus + len(e.fields) - 1) % len(e.fields)
+		return m, e.focusField()
+	case "ctrl+s", "enter":
+		req, err := e.request(time.Now())
+		if err != nil {
+			m.statusLine = err.Error()
+			return m, nil
+		}
+		e.mode = exportList
+		return m, m.createExportCmd(req)
+	}
+	var cmd tea.Cmd
+	e.fields[e.focus], cmd = e.fields[e.focus].Update(msg)
+	return m, cmd
+}
+
+func (e exportsPanel) request(now time.Time) (logs.ExportRequest, error) {
+	value := func(i int) string { return strings.TrimSpace(e.fields[i].Value()) }
+	from, err := logs.ParseTime(value(3), now)
+	if err != nil {
+		return logs.ExportRequest{}, err
+	}
+	to, err := logs.ParseEndTime(value(4), now)
+	if err != nil {
+		return logs.ExportRequest{}, err
+	}
+	return logs.ExportRequest{
+		LogGroup: value(0),
+		Bucket:   value(1),
+		Prefix:   value(2),
+		From:     from,
+		To:       to,
+	}, nil
+}
+
+func (m Model) renderExports() string {
+	e := m.exports
+	var b strings.Builder
+	src := m.sources[e.source]
+	fmt.Fprintf(&b, "%s %s\n", m.styles.title.Render("Export to S3"), m.styles.dim.Render(m.sourceLabel(src.profile, src.region)))
+	if e.mode == exportForm {
+		fmt.Fprintln(&b, m.styles.dim.Render("New export (tab next field, enter start, esc cancel)"))
+		for _, f := range e.fields {
+			fmt.Fprintln(&b, f.View())
+		}
+		if m.statusLine != "" {
+			fmt.Fprintf(&b, "\n%s\n", m.styles.status.Render(m.statusLine))
+		}
+		return b.String()
+	}
+
+	fmt.Fprintln(&b, m.styles.dim.Render(fmt.Sprintf("n new, c cancel, g reload, esc close (refreshes every %s)", exportRefresh)))
+	switch {
+	case e.mode == exportConfirmCancel:
+		fmt.Fprintln(&b, m.styles.warn.Render(fmt.Sprintf("cancel export %s? y/n", e.tasks[e.cursor].ID)))
+	case e.loading && len(e.tasks) == 0:
+		fmt.Fprintln(&b, m.styles.dim.Render("loading..."))
+	case len(e.tasks) == 0:
+		fmt.Fprintln(&b, "no export tasks")
+	}
+	start := 0
+	if e.cursor >= exportRows {
+		start = e.cursor - exportRows + 1
+	}
+	for i := start; i < len(e.tasks) && i < start+exportRows; i++ {
+		t := e.tasks[i]
+		dest := "s3://" + t.Bucket
+		if t.Prefix != "" {
+			dest += "/" + t.Prefix
+		}
+		span := fmt.Sprintf("%s → %s", m.times.format(t.From, time.Now()), m.times.format(t.To, time.Now()))
+		line := fmt.Sprintf("%-14s %-30s %s  %s", t.Status, t.LogGroup, dest, span)
+		switch {
+		case i == e.cursor:
+			line = m.styles.cursor.Render(line)
+		case t.Status == "FAILED":
+			line = m.styles.warn.Render(line)
+		}
+		fmt.Fprintln(&b, line)
+	}
+	if e.cursor < len(e.tasks) {
+		t := e.tasks[e.cursor]
+		detail := "task " + t.ID
+		if t.Name != "" {
+			detail += " (" + t.Name + ")"
+		}
+		if t.StatusMessage != "" {
+			detail += ": " + t.StatusMessage
+		}
+		fmt.Fprintf(&b, "\n%s\n", m.styles.dim.Render(detail))
+	}
+	if m.statusLine != "" {
+		fmt.Fprintf(&b, "\n%s\n", m.styles.status.Render(m.statusLine))
+	}
+	return b.String()
+}
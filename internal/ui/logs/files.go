@@ -0,0 +1,98 @@
+package logs
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/sachamama/sacha/internal/logs"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// localSource is the source key, and the label, of opened log files.
+const localSource = "local"
+
+type fileOpenedMsg struct {
+	group logs.LogGroup
+	err   error
+}
+
+type eventsExportedMsg struct {
+	path  string
+	count int
+	err   error
+}
+
+// openFileCmd reads a log file into the local source. The file source is
+// shared with the model, so parsing happens off the update loop.
+func (m Model) openFileCmd(path string) tea.Cmd {
+	files := m.files
+	return func() tea.Msg {
+		group, err := files.Open(path)
+		return fileOpenedMsg{group: group, err: err}
+	}
+}
+
+// fileOpened registers the local source on first use, selects the new file
+// and reloads the file list.
+func (m *Model) fileOpened(msg fileOpenedMsg) tea.Cmd {
+	if msg.err != nil {
+		m.statusLine = msg.err.Error()
+		return nil
+	}
+	src, ok := m.sources[localSource]
+	if !ok {
+		src = source{region: localSource, client: m.files}
+		m.addSource(src)
+	}
+	m.selected[groupRef{source: localSource, name: msg.group.Name}] = true
+	// Replay every open file from the start so the new one is not skipped.
+	delete(m.tailStarts, localSource)
+	// Commands may still read the old slice, e.g. a timeline export, so
+	// filter into a new one.
+	kept := make([]logs.TailEvent, 0, len(m.events))
+	for _, e := range m.events {
+		if sourceKey(e.Profile, e.Region) != localSource {
+			kept = append(kept, e)
+		}
+	}
+	m.events = kept
+	m.statusLine = "opened " + msg.group.Name
+	return m.loadLogGroupsCmd(src)
+}
+
+func (m *Model) closeFile(name string) {
+	m.files.Close(name)
+	delete(m.selected, groupRef{source: localSource, name: name})
+	groups, _, _ := m.files.ListLogGroups(context.Background(), nil)
+	m.sources[localSource].tagGroups(groups)
+	m.replaceGroups(localSource, groups)
+	if m.cursor >= len(m.filteredGroups()) && m.cursor > 0 {
+		m.cursor--
+	}
+	m.statusLine = "closed " + name
+}
+
+// exportEventsCmd writes events as NDJSON, which can be opened again later.
+func exportEventsCmd(path string, events []logs.TailEvent) tea.Cmd {
+	return func() tea.Msg {
+		f, err := os.Create(path)
+		if err != nil {
+			return eventsExportedMsg{path: path, err: err}
+		}
+		err = logs.WriteNDJSON(f, events)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		return eventsExportedMsg{path: path, count: len(events), err: err}
+	}
+}
+
+func (m *Model) eventsExported(msg eventsExportedMsg) {
+	if msg.err != nil {
+		m.statusLine = fmt.Sprintf("export %s: %v", msg.path, msg.err)
+		return
+	}
+	m.statusLine = fmt.Sprintf("wrote %d events to %s", msg.count, msg.path)
+}
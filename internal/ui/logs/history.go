@@ -0,0 +1,247 @@
+package logs
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sachamama/sacha/internal/history"
+	"github.com/sachamama/sacha/internal/logs"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const historyRows = 20
+
+type historySavedMsg struct {
+	err error
+}
+
+// recall steps through earlier inputs with the up and down arrows.
+type recall struct {
+	items []string
+	idx   int
+}
+
+func (r *recall) reset(items []string) {
+	r.items = items
+	r.idx = -1
+}
+
+// step moves through the items; stepping below the newest returns "".
+func (r *recall) step(delta int) (string, bool) {
+	idx := r.idx + delta
+	if idx < -1 || idx >= len(r.items) {
+		return "", false
+	}
+	r.idx = idx
+	if idx == -1 {
+		return "", true
+	}
+	return r.items[idx], true
+}
+
+// recallKey handles up/down in a text prompt; it reports whether the key
+// was consumed.
+func recallKey(in *textinput.Model, r *recall, msg tea.KeyMsg) bool {
+	delta := 0
+	switch msg.Type {
+	case tea.KeyUp:
+		delta = 1
+	case tea.KeyDown:
+		delta = -1
+	default:
+		return false
+	}
+	if text, ok := r.step(delta); ok {
+		in.SetValue(text)
+		in.CursorEnd()
+	}
+	return true
+}
+
+func (m Model) recallItems(kind string) []string {
+	if m.history == nil {
+		return nil
+	}
+	return m.history.Recall(kind, m.profile, m.primaryRegion())
+}
+
+// remember records an input with the current profile, region and selected
+// groups, and saves the history in the background.
+func (m Model) remember(kind, text string, groups []string) tea.Cmd {
+	return m.rememberOn(m.sources[m.primary], kind, text, groups)
+}
+
+// rememberOn records an entry that ran against src.
+func (m Model) rememberOn(src source, kind, text string, groups []string) tea.Cmd {
+	if m.history == nil || strings.TrimSpace(text) == "" {
+		return nil
+	}
+	m.history.Add(history.Entry{
+		Kind:    kind,
+		Text:    text,
+		Profile: src.profile,
+		Region:  src.region,
+		Groups:  groups,
+	})
+	return m.saveHistoryCmd()
+}
+
+func (m Model) saveHistoryCmd() tea.Cmd {
+	store := m.history
+	return func() tea.Msg {
+		return historySavedMsg{err: store.Save()}
+	}
+}
+
+func (m Model) selectedNames() []string {
+	refs := m.selectedGroups()
+	names := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		names = append(names, ref.name)
+	}
+	return names
+}
+
+// historyOverlay browses and fuzzy-searches the history.
+type historyOverlay struct {
+	active  bool
+	input   textinput.Model
+	results []history.Entry
+	cursor  int
+}
+
+func newHistoryOverlay() historyOverlay {
+	in := textinput.New()
+	in.Placeholder = "fuzzy search history"
+	in.Prompt = "history: "
+	return historyOverlay{input: in}
+}
+
+func (m *Model) openHistory() tea.Cmd {
+	if m.history == nil {
+		return nil
+	}
+	h := &m.historyView
+	h.active = true
+	h.cursor = 0
+	h.input.SetValue("")
+	h.results = m.history.Search("")
+	return h.input.Focus()
+}
+
+func (m Model) updateHistory(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	h := &m.historyView
+	switch msg.String() {
+	case "esc":
+		h.active = false
+		h.input.Blur()
+		return m, nil
+	case "up", "ctrl+p":
+		if h.cursor > 0 {
+			h.cursor--
+		}
+		return m, nil
+	case "down", "ctrl+n", "ctrl+r":
+		if h.cursor < len(h.results)-1 {
+			h.cursor++
+		}
+		return m, nil
+	case "ctrl+s":
+		if h.cursor < len(h.results) {
+			m.history.ToggleFavorite(h.results[h.cursor])
+			h.results = m.history.Search(h.input.Value())
+			return m, m.saveHistoryCmd()
+		}
+		return m, nil
+	case "enter":
+		h.active = false
+		h.input.Blur()
+		if h.cursor < len(h.results) {
+			return m.applyHistory(h.results[h.cursor])
+		}
+		return m, nil
+	}
+	var cmd tea.Cmd
+	prev := h.input.Value()
+	h.input, cmd = h.input.Update(msg)
+	if h.input.Value() != prev {
+		h.results = m.history.Search(h.input.Value())
+		h.cursor = 0
+	}
+	return m, cmd
+}
+
+// applyHistory re-runs an entry the way it was first used.
+func (m Model) applyHistory(e history.Entry) (tea.Model, tea.Cmd) {
+	switch e.Kind {
+	case history.KindGroupSearch:
+		m.search.SetValue(e.Text)
+		m.cursor = 0
+	case history.KindTailFilter:
+		f, err := logs.ParseFilter(e.Text)
+		if err != nil {
+			m.statusLine = err.Error()
+			return m, nil
+		}
+		m.setFilter(f)
+	case history.KindQuery:
+		// The query runs where it first ran, never quietly in another
+		// account or region.
+		key := m.primary
+		if e.Region != "" {
+			key = sourceKey(e.Profile, e.Region)
+		}
+		src, ok := m.sources[key]
+		if !ok {
+			m.statusLine = fmt.Sprintf("this query ran on %s, which is not loaded; add it first", key)
+			return m, nil
+		}
+		if _, err := m.queryRunner(key); err != nil {
+			m.statusLine = err.Error()
+			return m, nil
+		}
+		def := logs.QueryDefinition{Name: "(history)", Query: e.Text, LogGroups: e.Groups}
+		m.queries.active = true
+		m.queries.loading = true
+		m.queries.running = def
+		m.queries.source = key
+		m.queries.mode = queryRunning
+		return m, tea.Batch(m.listQueriesCmd(), m.runQueryCmd(key, def), m.rememberOn(src, e.Kind, e.Text, e.Groups))
+	}
+	return m, m.remember(e.Kind, e.Text, e.Groups)
+}
+
+func (m Model) renderHistory() string {
+	h := m.historyView
+	var b strings.Builder
+	fmt.Fprintln(&b, m.styles.title.Render("History"))
+	fmt.Fprintln(&b, h.input.View())
+	fmt.Fprintln(&b, m.styles.dim.Render("enter run, ctrl+s star, ↑/↓ move, esc close"))
+	if len(h.results) == 0 {
+		fmt.Fprintln(&b, "no history")
+		return b.String()
+	}
+	start := 0
+	if h.cursor >= historyRows {
+		start = h.cursor - historyRows + 1
+	}
+	for i := start; i < len(h.results) && i < start+historyRows; i++ {
+		e := h.results[i]
+		star := " "
+		if e.Favorite {
+			star = "★"
+		}
+		context := sourceKey(e.Profile, e.Region)
+		if len(e.Groups) > 0 {
+			context += " " + strings.Join(e.Groups, ",")
+		}
+		line := fmt.Sprintf("%s %-6s %s  %s", star, e.Kind, e.Text, m.styles.dim.Render(context))
+		if i == h.cursor {
+			line = m.styles.cursor.Render(fmt.Sprintf("%s %-6s %s  %s", star, e.Kind, e.Text, context))
+		}
+		fmt.Fprintln(&b, line)
+	}
+	return b.String()
+}
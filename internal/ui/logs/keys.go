@@ -0,0 +1,64 @@
+package logs
+
+import (
+	"github.com/sachamama/sacha/internal/keymap"
+)
+
+// KeyGroups are the default key bindings of the CloudWatch Logs view. Keys
+// inside overlays and prompts are fixed.
+var KeyGroups = []keymap.Group{
+	{Title: "Log groups", Actions: []keymap.Action{
+		{Name: "up", Help: "move up", Keys: []string{"up", "k"}},
+		{Name: "down", Help: "move down", Keys: []string{"down", "j"}},
+		{Name: "search", Help: "search log groups", Keys: []string{"/"}},
+		{Name: "select", Help: "select the group under the cursor", Keys: []string{"space"}},
+		{Name: "select-all", Help: "select or clear all log groups", Keys: []string{"a"}},
+		{Name: "tail", Help: "tail the selected log groups", Keys: []string{"t"}},
+		{Name: "add-region", Help: "add the log groups of another region", Keys: []string{"+"}},
+		{Name: "add-profile", Help: "add the log groups of another profile", Keys: []string{"@"}},
+		{Name: "remove-source", Help: "remove the region, profile or file under the cursor", Keys: []string{"-"}},
+		{Name: "open", Help: "open a local log file", Keys: []string{"o"}},
+		{Name: "history", Help: "search history", Keys: []string{"ctrl+r"}},
+		{Name: "queries", Help: "saved Logs Insights queries", Keys: []string{"Q"}},
+		{Name: "exports", Help: "export log groups to S3", Keys: []string{"X"}},
+		{Name: "write", Help: "write test events", Keys: []string{"w"}},
+	}},
+	{Title: "Tail", Actions: []keymap.Action{
+		{Name: "stop", Help: "stop tailing", Keys: []string{"esc"}},
+		{Name: "tail-down", Help: "move the tail cursor down", Keys: []string{"J", "shift+down"}},
+		{Name: "tail-up", Help: "move the tail cursor up", Keys: []string{"K", "shift+up"}},
+		{Name: "follow", Help: "follow the newest event", Keys: []string{"G"}},
+		{Name: "page-up", Help: "scroll up a page", Keys: []string{"pgup"}},
+		{Name: "page-down", Help: "scroll down a page", Keys: []string{"pgdown"}},
+		{Name: "scroll-left", Help: "scroll left", Keys: []string{"h", "shift+left"}},
+		{Name: "scroll-right", Help: "scroll right", Keys: []string{"l", "shift+right"}},
+		{Name: "expand", Help: "expand or collapse the event under the cursor", Keys: []string{"enter"}},
+		{Name: "wrap", Help: "wrap long lines", Keys: []string{"W"}},
+		{Name: "filter", Help: "filter the tail", Keys: []string{"f"}},
+		{Name: "fields", Help: "show the top values of a JSON field", Keys: []string{"F"}},
+		{Name: "multiline", Help: "join multi-line events", Keys: []string{"m"}},
+		{Name: "dedupe", Help: "collapse repeated lines", Keys: []string{"d"}},
+		{Name: "time", Help: "cycle the timestamp display", Keys: []string{"T"}},
+		{Name: "lag", Help: "show ingestion lag per event", Keys: []string{"L"}},
+		{Name: "follow-id", Help: "follow the request or trace ID under the cursor", Keys: []string{"c"}},
+		{Name: "lambda", Help: "Lambda invocation statistics", Keys: []string{"i"}},
+		{Name: "export-buffer", Help: "write the tail buffer as NDJSON", Keys: []string{"E"}},
+		{Name: "copy", Help: "copy the event under the cursor", Keys: []string{"y"}},
+		{Name: "links", Help: "copy AWS console links", Keys: []string{"Y"}},
+	}},
+	{Title: "Bookmarks", Actions: []keymap.Action{
+		{Name: "mark", Help: "bookmark the event under the cursor", Keys: []string{"b"}},
+		{Name: "bookmarks", Help: "list bookmarks", Keys: []string{"B"}},
+		{Name: "next-mark", Help: "jump to the next bookmark", Keys: []string{"n"}},
+		{Name: "prev-mark", Help: "jump to the previous bookmark", Keys: []string{"N"}},
+	}},
+	{Title: "Layout", Actions: []keymap.Action{
+		{Name: "focus", Help: "focus the other pane", Keys: []string{"tab"}},
+		{Name: "shrink", Help: "narrow the log group pane", Keys: []string{"<"}},
+		{Name: "grow", Help: "widen the log group pane", Keys: []string{">"}},
+		{Name: "zoom", Help: "zoom the focused pane", Keys: []string{"Z"}},
+	}},
+}
+
+// defaultKeys is used when the app does not pass a keymap.
+var defaultKeys = keymap.MustNew(nil, KeyGroups...)
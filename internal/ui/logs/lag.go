@@ -0,0 +1,55 @@
+package logs
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sachamama/sacha/internal/logs"
+)
+
+// lagKey groups lag samples per log group, qualified by source when several
+// sources are loaded.
+func (m Model) lagKey(e logs.TailEvent) string {
+	if m.multiSource() {
+		return sourceKey(e.Profile, e.Region) + " " + e.LogGroup
+	}
+	return e.LogGroup
+}
+
+// tailHeaderHeight is the number of lines renderTail prints above the viewport.
+func (m Model) tailHeaderHeight() int {
+	if len(m.lags.Keys()) > 0 {
+		return 2
+	}
+	return 1
+}
+
+// renderLagSummary prints p50/p95 ingestion lag per group on one line.
+// Groups whose lag is growing are highlighted and marked with an arrow.
+func (m Model) renderLagSummary() string {
+	keys := m.lags.Keys()
+	if len(keys) == 0 {
+		return ""
+	}
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		stats, ok := m.lags.Stats(k)
+		if !ok {
+			continue
+		}
+		part := fmt.Sprintf("%s p50 %s p95 %s", k, formatDuration(stats.P50), formatDuration(stats.P95))
+		if stats.Growing {
+			part = m.styles.warn.Render("↑ " + part)
+		}
+		parts = append(parts, part)
+	}
+	return m.styles.dim.Render("lag: ") + strings.Join(parts, m.styles.dim.Render(" · "))
+}
+
+func formatDuration(d time.Duration) string {
+	if d < time.Second {
+		return d.Round(time.Millisecond).String()
+	}
+	return d.Round(100 * time.Millisecond).String()
+}
@@ -0,0 +1,119 @@
+package logs
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sachamama/sacha/internal/logs"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+const slowestInvocations = 15
+
+// invocationsView summarizes Lambda invocations found in the tail buffer.
+type invocationsView struct {
+	active bool
+	cursor int
+}
+
+func (m Model) lambdaStats() logs.LambdaStats {
+	lambda := make([]logs.TailEvent, 0, len(m.events))
+	for _, e := range m.events {
+		if logs.IsLambdaGroup(e.LogGroup) {
+			lambda = append(lambda, e)
+		}
+	}
+	return logs.SummarizeInvocations(logs.ParseInvocations(lambda), slowestInvocations, m.functionKey)
+}
+
+// functionKey names the function of inv, qualified by source when several
+// sources are loaded.
+func (m Model) functionKey(inv logs.Invocation) string {
+	name := strings.TrimPrefix(inv.LogGroup, logs.LambdaGroupPrefix)
+	if m.multiSource() {
+		return sourceKey(inv.Anchor.Profile, inv.Anchor.Region) + " " + name
+	}
+	return name
+}
+
+func (m Model) updateInvocations(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	v := &m.invocations
+	switch msg.String() {
+	case "esc", "q":
+		v.active = false
+	case "up", "k":
+		if v.cursor > 0 {
+			v.cursor--
+		}
+	case "down", "j":
+		if v.cursor < len(m.lambdaStats().Slowest)-1 {
+			v.cursor++
+		}
+	case "enter":
+		slowest := m.lambdaStats().Slowest
+		if v.cursor < len(slowest) {
+			v.active = false
+			m.jumpTo(slowest[v.cursor].Anchor)
+		}
+	}
+	return m, nil
+}
+
+// jumpTo moves the tail cursor to e, clearing the tail filter if it hides e.
+func (m *Model) jumpTo(e logs.TailEvent) {
+	if !m.filter.Empty() && !m.filter.Match(e) {
+		m.filter = logs.Filter{}
+		m.statusLine = "tail filter cleared"
+	}
+	m.follow = false
+	m.cursorKey = eventKey(e)
+	m.refreshTail()
+}
+
+func (m Model) renderInvocations() string {
+	var b strings.Builder
+	fmt.Fprintln(&b, m.styles.title.Render("Lambda invocations"))
+	stats := m.lambdaStats()
+	if stats.Count == 0 {
+		fmt.Fprintln(&b, m.styles.dim.Render("no REPORT lines from /aws/lambda/* groups in the buffer (esc close)"))
+		return b.String()
+	}
+	fmt.Fprintf(&b, "invocations %d | cold starts %d (%.1f%%, avg init %s) | timeouts %d\n",
+		stats.Count, stats.ColdStarts, stats.ColdStartRate, formatDuration(stats.AvgInit), stats.Timeouts)
+	width := len("function")
+	for _, f := range stats.Functions {
+		width = max(width, ansi.StringWidth(f.Key))
+	}
+	fmt.Fprintln(&b, m.styles.dim.Render(fmt.Sprintf("%-*s %6s %9s %9s %9s %9s %5s %5s %15s", width,
+		"function", "count", "p50", "p90", "p99", "max", "cold", "t/o", "memory")))
+	for _, f := range stats.Functions {
+		line := fmt.Sprintf("%s%s %6d %9s %9s %9s %9s %5d %5d %8s %5.1f%%",
+			f.Key, strings.Repeat(" ", width-ansi.StringWidth(f.Key)), f.Count,
+			formatDuration(f.P50), formatDuration(f.P90), formatDuration(f.P99), formatDuration(f.Max),
+			f.ColdStarts, f.Timeouts, fmt.Sprintf("%d/%d MB", f.MaxMemoryMB, f.MemorySizeMB), f.MemoryHeadroom)
+		if f.MemorySizeMB > 0 && f.MemoryHeadroom < 10 {
+			line = m.styles.warn.Render(line)
+		}
+		fmt.Fprintln(&b, line)
+	}
+	fmt.Fprintln(&b, m.styles.dim.Render("slowest (enter jumps to log lines, esc close)"))
+	for i, inv := range stats.Slowest {
+		flags := ""
+		if inv.ColdStart {
+			flags += " cold"
+		}
+		if inv.TimedOut {
+			flags += " timeout"
+		}
+		line := fmt.Sprintf("%9s %4d MB %s %s%s", formatDuration(inv.Duration), inv.MaxMemoryUsedMB, inv.RequestID, strings.TrimPrefix(inv.LogGroup, logs.LambdaGroupPrefix), flags)
+		if i == m.invocations.cursor {
+			line = m.styles.cursor.Render(line)
+		} else if inv.TimedOut {
+			line = m.styles.warn.Render(line)
+		}
+		fmt.Fprintln(&b, line)
+	}
+	return b.String()
+}
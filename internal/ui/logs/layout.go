@@ -0,0 +1,78 @@
+package logs
+
+import (
+	"fmt"
+)
+
+// pane is one side of the split view.
+type pane int
+
+const (
+	paneGroups pane = iota
+	paneTail
+)
+
+const (
+	defaultSplit = 50
+	minSplit     = 20
+	maxSplit     = 80
+	splitStep    = 5
+)
+
+// paneWidths returns the outer widths of the log group and tail panes. A
+// zoomed layout gives the focused pane the whole width and the other none.
+func (m Model) paneWidths() (left, right int) {
+	if m.zoomed {
+		if m.focus == paneTail {
+			return 0, m.width
+		}
+		return m.width, 0
+	}
+	left = m.width * m.split / 100
+	return left, m.width - left
+}
+
+// tailWidth is the outer width the tail is laid out for. While the log
+// group pane is zoomed the tail keeps its split width.
+func (m Model) tailWidth() int {
+	if _, right := m.paneWidths(); right > 0 {
+		return right
+	}
+	return m.width - m.width*m.split/100
+}
+
+// switchFocus moves the keyboard to the other pane.
+func (m *Model) switchFocus() {
+	if m.focus == paneGroups {
+		m.focus = paneTail
+	} else {
+		m.focus = paneGroups
+	}
+	m.setViewportSize(m.bodyHeight())
+}
+
+// resizeSplit moves the divider by delta percent of the width.
+func (m *Model) resizeSplit(delta int) {
+	m.split = max(min(m.split+delta, maxSplit), minSplit)
+	m.settings.SplitPercent = m.split
+	m.statusLine = fmt.Sprintf("log groups %d%% | tail %d%%", m.split, 100-m.split)
+	m.setViewportSize(m.bodyHeight())
+}
+
+func (m *Model) toggleZoom() {
+	m.zoomed = !m.zoomed
+	m.settings.Zoom = m.zoomed
+	m.statusLine = "zoom off"
+	if m.zoomed {
+		m.statusLine = "zoom on (tab switches pane)"
+	}
+	m.setViewportSize(m.bodyHeight())
+}
+
+// splitFrom validates a configured split.
+func splitFrom(percent int) int {
+	if percent < minSplit || percent > maxSplit {
+		return defaultSplit
+	}
+	return percent
+}
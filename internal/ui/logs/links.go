@@ -0,0 +1,191 @@
+package logs
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	awsx "github.com/sachamama/sacha/internal/aws"
+	"github.com/sachamama/sacha/internal/logs"
+
+	"github.com/aymanbagabas/go-osc52/v2"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/term"
+)
+
+// defaultInsightsQuery opens Logs Insights on the newest events.
+const defaultInsightsQuery = "fields @timestamp, @logStream, @message\n| sort @timestamp desc\n| limit 100"
+
+// copyItem is something the links menu can put on the clipboard.
+type copyItem struct {
+	label string
+	text  string
+}
+
+// linksOverlay offers console links for the event or group under the
+// cursor, and the event text.
+type linksOverlay struct {
+	active bool
+	cursor int
+	items  []copyItem
+}
+
+type copiedMsg struct {
+	label string
+	err   error
+}
+
+// copyCmd puts text on the clipboard of the terminal with an OSC52 escape
+// sequence, which works over SSH. The sequence goes to stderr so it does not
+// interleave with the renderer's output; when stderr is redirected it would
+// never reach the terminal, so the copy fails.
+func copyCmd(item copyItem) tea.Cmd {
+	return func() tea.Msg {
+		if !term.IsTerminal(os.Stderr.Fd()) {
+			return copiedMsg{label: item.label, err: errors.New("stderr is not a terminal, so the clipboard cannot be reached")}
+		}
+		seq := osc52.New(item.text)
+		switch {
+		case os.Getenv("TMUX") != "":
+			seq = seq.Tmux()
+		case strings.HasPrefix(os.Getenv("TERM"), "screen"):
+			seq = seq.Screen()
+		}
+		_, err := seq.WriteTo(os.Stderr)
+		return copiedMsg{label: item.label, err: err}
+	}
+}
+
+func (m *Model) copied(msg copiedMsg) {
+	if msg.err != nil {
+		m.statusLine = fmt.Sprintf("copy %s: %v", msg.label, msg.err)
+		return
+	}
+	m.statusLine = "copied " + msg.label
+}
+
+// yankEvent copies the message of the event under the tail cursor.
+func (m Model) yankEvent() tea.Cmd {
+	e, ok := m.cursorEvent()
+	if !ok {
+		return nil
+	}
+	return copyCmd(copyItem{label: "event text", text: strings.TrimSpace(e.Message)})
+}
+
+func (m *Model) openLinks() {
+	items := m.linkItems()
+	if len(items) == 0 {
+		m.statusLine = "nothing to link here"
+		return
+	}
+	m.links = linksOverlay{active: true, items: items}
+}
+
+// linkItems builds the links for the event under the tail cursor, or for
+// the log group under the list cursor when not tailing. Local files have no
+// console links.
+func (m Model) linkItems() []copyItem {
+	var items []copyItem
+	if m.tailing {
+		e, ok := m.cursorEvent()
+		if !ok {
+			return nil
+		}
+		items = append(items, copyItem{label: "event text", text: strings.TrimSpace(e.Message)})
+		key := sourceKey(e.Profile, e.Region)
+		if key == localSource {
+			return items
+		}
+		if e.LogStream != "" {
+			items = append(items, copyItem{label: "log stream link", text: awsx.LogStreamURL(e.Region, e.LogGroup, e.LogStream, e.Timestamp)})
+		}
+		items = append(items,
+			copyItem{label: "log group link", text: awsx.LogGroupURL(e.Region, e.LogGroup)},
+			m.insightsItem(key, e.LogGroup, m.tailFrom, time.Now()))
+		return items
+	}
+	groups := m.filteredGroups()
+	if m.cursor >= len(groups) {
+		return nil
+	}
+	g := groups[m.cursor]
+	key := refOf(g).source
+	if key == localSource {
+		return nil
+	}
+	end := time.Now()
+	return []copyItem{
+		{label: "log group link", text: awsx.LogGroupURL(g.Region, g.Name)},
+		m.insightsItem(key, g.Name, end.Add(-defaultTailWindow), end),
+	}
+}
+
+// insightsItem links to Logs Insights over the selected groups of a source,
+// or fallback if none of its groups are selected.
+func (m Model) insightsItem(key, fallback string, start, end time.Time) copyItem {
+	groups := m.selectedBySource()[key]
+	if len(groups) == 0 {
+		groups = []string{fallback}
+	}
+	label := "Logs Insights link"
+	if len(groups) > 1 {
+		label += fmt.Sprintf(" (%d groups)", len(groups))
+	}
+	return copyItem{label: label, text: awsx.InsightsURL(m.sources[key].region, groups, defaultInsightsQuery, start, end)}
+}
+
+// queryLinkCmd copies a Logs Insights link for a query on the source under
+// key over the panel's time range.
+func (m Model) queryLinkCmd(key string, def logs.QueryDefinition) tea.Cmd {
+	groups := def.LogGroups
+	if len(groups) == 0 {
+		groups = m.selectedBySource()[key]
+	}
+	end := time.Now()
+	start := end.Add(-queryRanges[m.queries.rangeIdx])
+	return copyCmd(copyItem{
+		label: "Logs Insights link for " + def.Name,
+		text:  awsx.InsightsURL(m.sources[key].region, groups, def.Query, start, end),
+	})
+}
+
+func (m Model) updateLinks(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := &m.links
+	switch msg.String() {
+	case "esc", "q", "Y":
+		l.active = false
+	case "up", "k":
+		if l.cursor > 0 {
+			l.cursor--
+		}
+	case "down", "j":
+		if l.cursor < len(l.items)-1 {
+			l.cursor++
+		}
+	case "enter", "y":
+		l.active = false
+		if l.cursor < len(l.items) {
+			return m, copyCmd(l.items[l.cursor])
+		}
+	}
+	return m, nil
+}
+
+func (m Model) renderLinks() string {
+	var b strings.Builder
+	fmt.Fprintln(&b, m.styles.title.Render("Copy"))
+	fmt.Fprintln(&b, m.styles.dim.Render("enter copies to the clipboard (OSC52), esc close"))
+	for i, item := range m.links.items {
+		line := item.label
+		if i == m.links.cursor {
+			line = m.styles.cursor.Render(line)
+		}
+		fmt.Fprintln(&b, line)
+		first, _, _ := strings.Cut(item.text, "\n")
+		fmt.Fprintln(&b, "  "+m.styles.dim.Render(first))
+	}
+	return b.String()
+}
@@ -2,12 +2,19 @@ package logs
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	awsx "github.com/sachamama/sacha/internal/aws"
+	"github.com/sachamama/sacha/internal/config"
+	"github.com/sachamama/sacha/internal/history"
+	"github.com/sachamama/sacha/internal/keymap"
 	"github.com/sachamama/sacha/internal/logs"
+	"github.com/sachamama/sacha/internal/theme"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
@@ -18,79 +25,275 @@ import (
 const (
 	defaultTailWindow   = 15 * time.Minute
 	defaultPollInterval = 5 * time.Second
+	horizontalStep      = 10 // columns per h/l press
 )
 
 type logGroupsLoadedMsg struct {
+	source string
 	groups []logs.LogGroup
 	err    error
 }
 
 type tailUpdateMsg struct {
 	events    []logs.TailEvent
-	nextStart time.Time
+	nextStart map[string]time.Time
 	err       error
+	// login asks for an SSO login when a source failed for want of a
+	// valid token; polling resumes with it.
+	login *awsx.SSOLoginMsg
 }
 
 type pollTailMsg struct{}
 
 type Model struct {
-	client *logs.Client
+	sources     map[string]source
+	sourceOrder []string
+	primary     string
+	loader      awsx.Loader
+	profile     string
+	settings    *config.Config
+	history     *history.Store
+	files       *logs.FileSource
+	openOnStart []string
+	// resolveAccount looks up the account of the primary source on Init.
+	resolveAccount tea.Cmd
+	demo           bool
 
 	width  int
 	height int
 
-	logGroups []logs.LogGroup
-	cursor    int
-	selected  map[string]bool
-	loading   bool
+	logGroups   []logs.LogGroup
+	cursor      int
+	groupOffset int
+	selected    map[groupRef]bool
+	loading     bool
+	focus       pane
+	split       int
+	zoomed      bool
+	keys        *keymap.Keymap
+	styles      styles
 
 	searching  bool
 	search     textinput.Model
 	statusLine string
 
+	prompt      sourcePrompt
+	sourceInput textinput.Model
+
 	tailing      bool
-	tailStart    time.Time
+	tailFrom     time.Time
+	tailStarts   map[string]time.Time
 	pollInterval time.Duration
 	events       []logs.TailEvent
 	view         viewport.Model
+	times        timeDisplay
+	lags         *logs.LagTracker
+	showLag      bool
+
+	filtering   bool
+	filterInput textinput.Model
+	filter      logs.Filter
+	stats       statsOverlay
+
+	multiline   bool
+	dedupe      bool
+	wrap        bool
+	expanded    map[string]bool
+	follow      bool
+	cursorKey   string
+	trace       traceView
+	invocations invocationsView
+	queries     queriesPanel
+	exports     exportsPanel
+	write       writePanel
+	retention   retentionConfirm
+	historyView historyOverlay
+	marks       []logs.Bookmark
+	bookmarks   bookmarksOverlay
+	links       linksOverlay
+
+	searchRecall recall
+	filterRecall recall
 }
 
-func NewModel(client *logs.Client) Model {
+// NewModel builds the CloudWatch Logs model around the source of the active
+// profile and region. Additional regions and profiles are loaded on demand
+// through opts.Loader.
+func NewModel(region string, client logs.Source, opts awsx.ServiceOptions) Model {
 	ti := textinput.New()
 	ti.Placeholder = "filter log groups"
 	ti.Prompt = "/ "
-	return Model{
-		client:       client,
-		selected:     map[string]bool{},
+	si := textinput.New()
+	fi := textinput.New()
+	fi.Placeholder = "text or $.field=value"
+	fi.Prompt = "filter: "
+	settings := opts.Config
+	if settings == nil {
+		settings = &config.Config{}
+	}
+	keys := opts.Keys
+	if keys == nil {
+		keys = defaultKeys
+	}
+	th := opts.Theme
+	if th == nil {
+		th = theme.Default()
+	}
+	m := Model{
+		sources:      map[string]source{},
+		primary:      sourceKey(opts.Profile, region),
+		loader:       opts.Loader,
+		profile:      opts.Profile,
+		settings:     settings,
+		keys:         keys,
+		styles:       newStyles(th),
+		history:      opts.History,
+		files:        logs.NewFileSource(),
+		openOnStart:  opts.Files,
+		demo:         opts.Demo,
+		selected:     map[groupRef]bool{},
+		expanded:     map[string]bool{},
 		loading:      true,
 		search:       ti,
+		sourceInput:  si,
+		filterInput:  fi,
+		stats:        newStatsOverlay(),
+		queries:      newQueriesPanel(),
+		exports:      newExportsPanel(),
+		write:        newWritePanel(),
+		historyView:  newHistoryOverlay(),
+		bookmarks:    newBookmarksOverlay(),
+		tailStarts:   map[string]time.Time{},
 		pollInterval: defaultPollInterval,
+		split:        splitFrom(settings.SplitPercent),
+		zoomed:       settings.Zoom,
+		lags:         logs.NewLagTracker(logs.DefaultLagWindow),
 	}
+	m.addSource(source{profile: opts.Profile, region: region, client: client})
+	times, err := newTimeDisplay(settings)
+	if err != nil {
+		m.statusLine = err.Error()
+	}
+	m.times = times
+	return m
 }
 
 func (m Model) Init() tea.Cmd {
-	return m.loadLogGroupsCmd()
+	cmds := []tea.Cmd{m.loadLogGroupsCmd(m.sources[m.primary]), m.resolveAccount}
+	for _, path := range m.openOnStart {
+		cmds = append(cmds, m.openFileCmd(path))
+	}
+	return tea.Batch(cmds...)
 }
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	// Keep the list where it was last drawn.
+	m.groupOffset = m.groupStart()
 	switch msg := msg.(type) {
+	case tea.MouseMsg:
+		return m.updateMouse(msg)
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
 		m.setViewportSize(m.bodyHeight())
 	case logGroupsLoadedMsg:
 		m.loading = false
+		if src, ok := m.sources[msg.source]; ok && awsx.IsSSOTokenError(msg.err) {
+			m.statusLine = msg.err.Error()
+			return m, func() tea.Msg {
+				return awsx.SSOLoginMsg{Profile: src.profile, Retry: m.loadLogGroupsCmd(src)}
+			}
+		}
 		if msg.err != nil {
+			// Secondary sources with bad credentials are dropped so the
+			// remaining ones keep working.
+			if msg.source != m.primary {
+				m.dropSource(msg.source)
+			}
 			m.statusLine = msg.err.Error()
 			return m, nil
 		}
-		m.logGroups = msg.groups
+		if _, ok := m.sources[msg.source]; !ok {
+			return m, nil
+		}
+		m.replaceGroups(msg.source, msg.groups)
 		m.statusLine = fmt.Sprintf("loaded %d log groups", len(msg.groups))
+		if m.multiSource() {
+			m.statusLine += " from " + msg.source
+		}
+	case accountResolvedMsg:
+		m.accountResolved(msg)
+	case sourceAddedMsg:
+		if msg.err != nil {
+			m.statusLine = msg.err.Error()
+			return m, nil
+		}
+		m.addSource(msg.source)
+		m.loading = true
+		return m, m.loadLogGroupsCmd(msg.source)
+	case fileOpenedMsg:
+		return m, m.fileOpened(msg)
+	case eventsExportedMsg:
+		m.eventsExported(msg)
+	case timelineWrittenMsg:
+		m.timelineWritten(msg)
+	case awsx.CommandMsg:
+		return m.runCommand(msg)
+	case retentionSetMsg:
+		m.retentionSet(msg)
+	case copiedMsg:
+		m.copied(msg)
 	case tea.KeyMsg:
+		if m.retention.active {
+			return m.updateRetention(msg)
+		}
+		if m.prompt != promptNone {
+			return m.updateSourcePrompt(msg)
+		}
+		if m.filtering {
+			return m.updateFilterPrompt(msg)
+		}
+		if m.bookmarks.noting {
+			return m.updateNote(msg)
+		}
+		if m.bookmarks.active {
+			return m.updateBookmarks(msg)
+		}
+		if m.links.active {
+			return m.updateLinks(msg)
+		}
+		if m.stats.active {
+			return m.updateStats(msg)
+		}
+		if m.trace.active {
+			return m.updateTrace(msg)
+		}
+		if m.invocations.active {
+			return m.updateInvocations(msg)
+		}
+		if m.queries.active {
+			return m.updateQueries(msg)
+		}
+		if m.historyView.active {
+			return m.updateHistory(msg)
+		}
+		if m.exports.active {
+			return m.updateExports(msg)
+		}
+		if m.write.active {
+			return m.updateWrite(msg)
+		}
 		if m.searching {
 			switch msg.Type {
-			case tea.KeyEnter, tea.KeyEscape:
+			case tea.KeyEscape:
+				m.searching = false
+				return m, nil
+			case tea.KeyEnter:
 				m.searching = false
+				return m, m.remember(history.KindGroupSearch, m.search.Value(), nil)
+			}
+			if recallKey(&m.search, &m.searchRecall, msg) {
+				m.cursor = 0
 				return m, nil
 			}
 			var cmd tea.Cmd
@@ -98,62 +301,53 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, cmd
 		}
 
-		switch msg.String() {
-		case "up", "k":
-			if m.cursor > 0 {
-				m.cursor--
-			}
-		case "down", "j":
-			if m.cursor < len(m.filteredGroups())-1 {
-				m.cursor++
-			}
-		case "/":
-			m.searching = true
-			return m, m.search.Focus()
-		case " ":
-			m.toggleSelection()
-		case "a":
-			m.toggleAll()
-		case "t":
-			if len(m.selectedGroups()) > 0 {
-				m.tailing = true
-				m.events = nil
-				m.tailStart = time.Now().Add(-defaultTailWindow)
-				m.view = viewport.Model{}
-				m.setViewportSize(m.bodyHeight())
-				return m, m.pollTailCmd()
-			}
-		case "q", "esc":
-			if m.tailing {
-				m.tailing = false
-			}
-		case "pgup", "pgdn":
-			if m.tailing {
-				var cmd tea.Cmd
-				m.view, cmd = m.view.Update(msg)
-				return m, cmd
-			}
-		}
+		return m.runAction(m.keys.Action(msg))
 	case pollTailMsg:
 		if !m.tailing {
 			return m, nil
 		}
 		return m, m.pollTailCmd()
+	case traceLoadedMsg:
+		m.applyTrace(msg)
+	case queryDefsLoadedMsg, queryChangedMsg, queryResultMsg:
+		return m, m.updateQueriesMsg(msg)
+	case exportTasksLoadedMsg, exportChangedMsg, exportTickMsg:
+		return m, m.updateExportsMsg(msg)
+	case streamsLoadedMsg, eventsWrittenMsg, editorDoneMsg:
+		return m, m.updateWriteMsg(msg)
+	case historySavedMsg:
+		if msg.err != nil {
+			m.statusLine = msg.err.Error()
+		}
 	case tailUpdateMsg:
+		// A failing source must not stop the others, so keep what arrived.
 		if msg.err != nil {
 			m.statusLine = msg.err.Error()
-			return m, nil
+		}
+		for key, next := range msg.nextStart {
+			m.tailStarts[key] = next
 		}
 		if len(msg.events) > 0 {
-			m.tailStart = msg.nextStart
-			m.events = append(m.events, msg.events...)
+			header := m.tailHeaderHeight()
+			m.lags.Observe(msg.events, m.lagKey)
+			// The lag summary line appears with the first samples.
+			if m.tailHeaderHeight() != header {
+				m.setViewportSize(m.bodyHeight())
+			}
+			m.events = logs.MergeEvents(m.events, msg.events)
 			if len(m.events) > 1000 {
 				m.events = m.events[len(m.events)-1000:]
 			}
-			m.view.SetContent(renderEvents(m.events))
 		}
+		// Re-render on every poll so relative timestamps keep moving.
+		m.refreshTail()
 		if m.tailing {
-			return m, tea.Tick(m.pollInterval, func(time.Time) tea.Msg { return pollTailMsg{} })
+			poll := tea.Tick(m.pollInterval, func(time.Time) tea.Msg { return pollTailMsg{} })
+			if msg.login != nil {
+				login := *msg.login
+				return m, tea.Batch(poll, func() tea.Msg { return login })
+			}
+			return m, poll
 		}
 	}
 
@@ -165,21 +359,45 @@ func (m Model) View() string {
 		return "loading..."
 	}
 
-	leftWidth := m.width / 2
-	rightWidth := m.width - leftWidth
+	leftWidth, rightWidth := m.paneWidths()
 	bodyHeight := m.bodyHeight()
 
+	if m.retention.active {
+		return m.styles.panel.Width(m.width - 2).Height(bodyHeight).Render(m.renderRetention())
+	}
+	if m.queries.active {
+		return m.styles.panel.Width(m.width - 2).Height(bodyHeight).Render(m.renderQueries())
+	}
+	if m.exports.active {
+		return m.styles.panel.Width(m.width - 2).Height(bodyHeight).Render(m.renderExports())
+	}
+	if m.write.active {
+		return m.styles.panel.Width(m.width - 2).Height(bodyHeight).Render(m.renderWrite())
+	}
+
 	if m.tailing {
 		m.setViewportSize(bodyHeight)
 	}
 
-	left := panelStyle.Width(leftWidth).Height(bodyHeight).Render(m.renderGroups())
-	right := panelStyle.Width(rightWidth).Height(bodyHeight).Render(m.renderTail())
-
+	leftStyle, rightStyle := m.styles.panel, m.styles.panel
+	if m.focus == paneTail {
+		rightStyle = m.styles.focusedPanel
+	} else {
+		leftStyle = m.styles.focusedPanel
+	}
+	// Widths are outer widths; lipgloss adds the border outside Width.
+	if rightWidth == 0 {
+		return leftStyle.Width(leftWidth - 2).Height(bodyHeight).Render(m.renderGroups())
+	}
+	right := rightStyle.Width(rightWidth - 2).Height(bodyHeight).Render(m.renderTail())
+	if leftWidth == 0 {
+		return right
+	}
+	left := leftStyle.Width(leftWidth - 2).Height(bodyHeight).Render(m.renderGroups())
 	return lipgloss.JoinHorizontal(lipgloss.Top, left, right)
 }
 
-func (m Model) loadLogGroupsCmd() tea.Cmd {
+func (m Model) loadLogGroupsCmd(src source) tea.Cmd {
 	return func() tea.Msg {
 		ctx := context.Background()
 		var (
@@ -187,9 +405,12 @@ func (m Model) loadLogGroupsCmd() tea.Cmd {
 			token *string
 		)
 		for {
-			groups, next, err := m.client.ListLogGroups(ctx, token)
+			groups, next, err := src.client.ListLogGroups(ctx, token)
 			if err != nil {
-				return logGroupsLoadedMsg{err: err}
+				if len(m.sources) > 1 {
+					err = fmt.Errorf("%s: %w", src.key(), err)
+				}
+				return logGroupsLoadedMsg{source: src.key(), err: err}
 			}
 			all = append(all, groups...)
 			if next == nil || aws.ToString(next) == "" {
@@ -197,17 +418,66 @@ func (m Model) loadLogGroupsCmd() tea.Cmd {
 			}
 			token = next
 		}
-		return logGroupsLoadedMsg{groups: all}
+		src.tagGroups(all)
+		return logGroupsLoadedMsg{source: src.key(), groups: all}
 	}
 }
 
+// pollTailCmd fetches every source concurrently and merges the results by
+// timestamp. Each source resumes from its own position so a slow region or
+// account does not skip events.
 func (m Model) pollTailCmd() tea.Cmd {
-	groups := m.selectedGroups()
-	start := m.tailStart
+	type job struct {
+		src    source
+		groups []string
+		start  time.Time
+	}
+	jobs := []job{}
+	bySource := m.selectedBySource()
+	for _, key := range m.sourceOrder {
+		groups := bySource[key]
+		if len(groups) == 0 {
+			continue
+		}
+		src := m.sources[key]
+		start, ok := m.tailStarts[key]
+		if !ok && !src.replay() {
+			start = m.tailFrom
+		}
+		jobs = append(jobs, job{src: src, groups: groups, start: start})
+	}
 	return func() tea.Msg {
 		ctx := context.Background()
-		events, next, err := m.client.FetchEvents(ctx, groups, start)
-		return tailUpdateMsg{events: events, nextStart: next, err: err}
+		batches := make([][]logs.TailEvent, len(jobs))
+		nexts := make([]time.Time, len(jobs))
+		errs := make([]error, len(jobs))
+		var wg sync.WaitGroup
+		for i, j := range jobs {
+			wg.Add(1)
+			go func(i int, j job) {
+				defer wg.Done()
+				events, next, err := j.src.client.FetchEvents(ctx, j.groups, j.start)
+				if err != nil {
+					errs[i] = fmt.Errorf("%s: %w", j.src.key(), err)
+					return
+				}
+				j.src.tagEvents(events)
+				batches[i] = events
+				nexts[i] = next
+			}(i, j)
+		}
+		wg.Wait()
+
+		nextStart := map[string]time.Time{}
+		var login *awsx.SSOLoginMsg
+		for i, j := range jobs {
+			if errs[i] == nil {
+				nextStart[j.src.key()] = nexts[i]
+			} else if login == nil && awsx.IsSSOTokenError(errs[i]) {
+				login = &awsx.SSOLoginMsg{Profile: j.src.profile}
+			}
+		}
+		return tailUpdateMsg{events: logs.MergeEvents(batches...), nextStart: nextStart, err: errors.Join(errs...), login: login}
 	}
 }
 
@@ -236,34 +506,43 @@ func (m *Model) toggleSelection() {
 	if len(groups) == 0 || m.cursor >= len(groups) {
 		return
 	}
-	name := groups[m.cursor].Name
-	if m.selected[name] {
-		delete(m.selected, name)
+	ref := refOf(groups[m.cursor])
+	if m.selected[ref] {
+		delete(m.selected, ref)
 	} else {
-		m.selected[name] = true
+		m.selected[ref] = true
 	}
 }
 
 func (m *Model) toggleAll() {
 	if len(m.selected) == len(m.logGroups) {
-		m.selected = map[string]bool{}
+		m.selected = map[groupRef]bool{}
 		return
 	}
 	for _, g := range m.logGroups {
-		m.selected[g.Name] = true
+		m.selected[refOf(g)] = true
 	}
 }
 
-func (m Model) selectedGroups() []string {
-	out := make([]string, 0, len(m.selected))
-	for name, ok := range m.selected {
+func (m Model) selectedGroups() []groupRef {
+	out := make([]groupRef, 0, len(m.selected))
+	for ref, ok := range m.selected {
 		if ok {
-			out = append(out, name)
+			out = append(out, ref)
 		}
 	}
 	return out
 }
 
+// selectedBySource groups the selected log group names by their source key.
+func (m Model) selectedBySource() map[string][]string {
+	out := map[string][]string{}
+	for _, ref := range m.selectedGroups() {
+		out[ref.source] = append(out[ref.source], ref.name)
+	}
+	return out
+}
+
 func (m Model) selectedCount() int {
 	count := 0
 	for _, ok := range m.selected {
@@ -279,22 +558,34 @@ func (m Model) Tailing() bool {
 	return m.tailing
 }
 
+// Capturing reports whether a text prompt currently owns the keyboard.
+func (m Model) Capturing() bool {
+	return m.retention.active || m.searching || m.prompt != promptNone || m.filtering || m.stats.active || m.trace.active || m.invocations.active || m.queries.active || m.historyView.active || m.exports.active || m.write.active || m.bookmarks.active || m.bookmarks.noting || m.links.active
+}
+
 func (m *Model) setViewportSize(bodyHeight int) {
 	if !m.tailing {
 		return
 	}
-	rightWidth := m.width - m.width/2
+	rightWidth := m.tailWidth()
 	innerWidth := rightWidth - 4 // account for border/padding
 	if innerWidth < 20 {
-		innerWidth = rightWidth
+		innerWidth = max(rightWidth-2, 1)
 	}
-	contentHeight := bodyHeight - 2  // panel borders
-	innerHeight := contentHeight - 1 // header inside panel
+	contentHeight := bodyHeight - 2                     // panel borders
+	innerHeight := contentHeight - m.tailHeaderHeight() // header inside panel
 	if innerHeight < 1 {
 		innerHeight = 1
 	}
+	// Wrapped rows depend on the width, so re-render when it changes.
+	resized := m.view.Width != innerWidth
 	m.view.Width = innerWidth
 	m.view.Height = innerHeight
+	if resized && m.wrap {
+		m.refreshTail()
+	}
+	m.trace.view.Width = innerWidth
+	m.trace.view.Height = max(contentHeight-2, 1)
 }
 
 func (m Model) bodyHeight() int {
@@ -304,3 +595,177 @@ func (m Model) bodyHeight() int {
 	}
 	return h
 }
+
+// runAction runs a bound action of the main view. The quit key of the app
+// reaches the service only while tailing, and stops the tail.
+func (m Model) runAction(action string) (tea.Model, tea.Cmd) {
+	switch action {
+	case "up":
+		if m.focus == paneTail {
+			m.moveTailCursor(-1)
+		} else if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down":
+		if m.focus == paneTail {
+			m.moveTailCursor(1)
+		} else if m.cursor < len(m.filteredGroups())-1 {
+			m.cursor++
+		}
+	case "search":
+		m.searching = true
+		m.searchRecall.reset(m.recallItems(history.KindGroupSearch))
+		return m, m.search.Focus()
+	case "history":
+		return m, m.openHistory()
+	case "focus":
+		m.switchFocus()
+	case "shrink":
+		m.resizeSplit(-splitStep)
+	case "grow":
+		m.resizeSplit(splitStep)
+	case "zoom":
+		m.toggleZoom()
+	case "select":
+		m.toggleSelection()
+	case "select-all":
+		m.toggleAll()
+	case "add-region":
+		return m, m.openSourcePrompt(promptRegion)
+	case "add-profile":
+		return m, m.openSourcePrompt(promptProfile)
+	case "remove-source":
+		m.removeSource()
+	case "open":
+		return m, m.openSourcePrompt(promptFile)
+	case "export-buffer":
+		if m.tailing {
+			return m, m.openSourcePrompt(promptExport)
+		}
+	case "time":
+		m.times = m.times.next()
+		m.settings.TimeDisplay = m.times.mode
+		m.statusLine = "timestamps: " + m.times.label()
+		m.refreshTail()
+	case "lag":
+		m.showLag = !m.showLag
+		m.refreshTail()
+	case "filter":
+		if m.tailing {
+			return m, m.openFilterPrompt()
+		}
+	case "fields":
+		if m.tailing {
+			return m, m.stats.open()
+		}
+	case "tail-down":
+		m.moveTailCursor(1)
+	case "tail-up":
+		m.moveTailCursor(-1)
+	case "follow":
+		m.follow = true
+		m.refreshTail()
+	case "multiline":
+		m.multiline = !m.multiline
+		m.statusLine = "multi-line joining off"
+		if m.multiline {
+			m.statusLine = "multi-line joining on" + m.hint("expand", "expands")
+		}
+		m.refreshTail()
+	case "dedupe":
+		m.dedupe = !m.dedupe
+		m.statusLine = "repeated lines shown"
+		if m.dedupe {
+			m.statusLine = "repeated lines collapsed" + m.hint("expand", "expands")
+		}
+		m.refreshTail()
+	case "wrap":
+		m.toggleWrap()
+	case "scroll-left":
+		if m.tailing && !m.wrap {
+			m.view.ScrollLeft(horizontalStep)
+		}
+	case "scroll-right":
+		if m.tailing && !m.wrap {
+			m.view.ScrollRight(horizontalStep)
+		}
+	case "expand":
+		if m.tailing {
+			m.toggleExpanded()
+		}
+	case "mark":
+		if m.tailing {
+			return m, m.toggleMark()
+		}
+	case "bookmarks":
+		if m.tailing {
+			m.openBookmarks()
+		}
+	case "next-mark":
+		if m.tailing {
+			m.stepMark(1)
+		}
+	case "prev-mark":
+		if m.tailing {
+			m.stepMark(-1)
+		}
+	case "copy":
+		if m.tailing {
+			return m, m.yankEvent()
+		}
+	case "links":
+		m.openLinks()
+	case "follow-id":
+		if m.tailing {
+			return m.startTrace()
+		}
+	case "lambda":
+		if m.tailing {
+			m.invocations = invocationsView{active: true}
+		}
+	case "queries":
+		return m, m.openQueries()
+	case "exports":
+		return m, m.openExports()
+	case "write":
+		return m, m.openWrite()
+	case "tail":
+		if len(m.selectedGroups()) > 0 {
+			m.tailing = true
+			m.events = nil
+			m.tailFrom = time.Now().Add(-defaultTailWindow)
+			m.tailStarts = map[string]time.Time{}
+			m.lags = logs.NewLagTracker(logs.DefaultLagWindow)
+			m.follow = true
+			m.expanded = map[string]bool{}
+			if m.zoomed {
+				m.focus = paneTail
+			}
+			m.view = viewport.New(0, 0)
+			m.setViewportSize(m.bodyHeight())
+			return m, m.pollTailCmd()
+		}
+	case "stop", "quit":
+		if m.tailing {
+			m.tailing = false
+			m.focus = paneGroups
+		}
+	case "page-up":
+		if m.tailing {
+			m.view.PageUp()
+		}
+	case "page-down":
+		if m.tailing {
+			m.view.PageDown()
+		}
+	}
+	return m, nil
+}
+
+// hint renders " (keys text)" for an action, or nothing if it is unbound.
+func (m Model) hint(action, text string) string {
+	if h := m.keys.Hint(action, text); h != "" {
+		return " (" + h + ")"
+	}
+	return ""
+}
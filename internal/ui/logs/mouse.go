@@ -0,0 +1,123 @@
+package logs
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	groupListTop    = 3 // border, title and search line above the first group
+	groupListFooter = 3 // blank line, counts and status line below the list
+	wheelEvents     = 1 // tail events moved per wheel step
+)
+
+// groupRows is how many log groups fit in the list pane.
+func (m Model) groupRows() int {
+	return max(m.bodyHeight()-groupListTop-groupListFooter-1, 1)
+}
+
+// groupStart is the first group shown. The list keeps its scroll position
+// until the cursor leaves the window.
+func (m Model) groupStart() int {
+	rows := m.groupRows()
+	start := m.groupOffset
+	if m.cursor < start {
+		start = m.cursor
+	}
+	if m.cursor >= start+rows {
+		start = m.cursor - rows + 1
+	}
+	return max(min(start, len(m.filteredGroups())-rows), 0)
+}
+
+// paneAt returns the pane under column x of the body.
+func (m Model) paneAt(x int) pane {
+	if left, _ := m.paneWidths(); x < left {
+		return paneGroups
+	}
+	return paneTail
+}
+
+// updateMouse handles wheel scrolling and clicks in both panes. Y is
+// relative to the top of the body. Overlays and prompts ignore the mouse.
+func (m Model) updateMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if m.Capturing() || msg.Action != tea.MouseActionPress {
+		return m, nil
+	}
+	p := m.paneAt(msg.X)
+	if p == paneTail && !m.tailing {
+		return m, nil
+	}
+	switch msg.Button {
+	case tea.MouseButtonWheelUp, tea.MouseButtonWheelDown:
+		delta := 1
+		if msg.Button == tea.MouseButtonWheelUp {
+			delta = -1
+		}
+		if p == paneTail {
+			m.moveTailCursor(delta * wheelEvents)
+		} else {
+			m.moveGroupCursor(delta)
+		}
+	case tea.MouseButtonLeft:
+		m.focus = p
+		if p == paneTail {
+			m.clickTail(msg.Y)
+		} else {
+			m.clickGroup(msg.X, msg.Y)
+		}
+	}
+	return m, nil
+}
+
+func (m *Model) moveGroupCursor(delta int) {
+	m.cursor = max(min(m.cursor+delta, len(m.filteredGroups())-1), 0)
+}
+
+// clickGroup moves the cursor to the clicked group. Clicking the checkbox,
+// or the group already under the cursor, toggles its selection.
+func (m *Model) clickGroup(x, y int) {
+	row := y - groupListTop
+	if row < 0 || row >= m.groupRows() {
+		return
+	}
+	idx := m.groupStart() + row
+	if idx >= len(m.filteredGroups()) {
+		return
+	}
+	onCheckbox := x >= 2 && x <= 4
+	if idx == m.cursor || onCheckbox {
+		m.cursor = idx
+		m.toggleSelection()
+		return
+	}
+	m.cursor = idx
+}
+
+// clickTail moves the tail cursor to the clicked event. Clicking the event
+// already under the cursor expands or collapses it.
+func (m *Model) clickTail(y int) {
+	line := y - 1 - m.tailHeaderHeight() + m.view.YOffset
+	if line < m.view.YOffset || line >= m.view.YOffset+m.view.Height {
+		return
+	}
+	visible := m.visibleEvents()
+	_, starts := m.renderEvents(visible, -1)
+	idx := -1
+	for i, start := range starts {
+		if start > line {
+			break
+		}
+		idx = i
+	}
+	if idx < 0 {
+		return
+	}
+	key := eventKey(visible[idx])
+	if !m.follow && key == m.cursorKey {
+		m.toggleExpanded()
+		return
+	}
+	m.follow = false
+	m.cursorKey = key
+	m.refreshTail()
+}
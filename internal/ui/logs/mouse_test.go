@@ -0,0 +1,91 @@
+package logs
+
+import (
+	"fmt"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	awsx "github.com/sachamama/sacha/internal/aws"
+	"github.com/sachamama/sacha/internal/logs"
+)
+
+// mouseModel returns a model with n groups in a 120x30 window.
+func mouseModel(t *testing.T, n int) Model {
+	t.Helper()
+	m := NewModel("local", logs.NewFileSource(), awsx.ServiceOptions{})
+	next, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 30})
+	m = next.(Model)
+	m.loading = false
+	for i := range n {
+		m.logGroups = append(m.logGroups, logs.LogGroup{Name: fmt.Sprintf("/g/%02d", i), Region: "local"})
+	}
+	return m
+}
+
+func press(m Model, button tea.MouseButton, x, y int) Model {
+	next, _ := m.Update(tea.MouseMsg{X: x, Y: y, Button: button, Action: tea.MouseActionPress})
+	return next.(Model)
+}
+
+func TestMouseGroupList(t *testing.T) {
+	m := mouseModel(t, 5)
+
+	m = press(m, tea.MouseButtonWheelDown, 10, 5)
+	if m.cursor != 1 {
+		t.Fatalf("wheel down: cursor %d, want 1", m.cursor)
+	}
+	m = press(m, tea.MouseButtonWheelUp, 10, 5)
+	m = press(m, tea.MouseButtonWheelUp, 10, 5)
+	if m.cursor != 0 {
+		t.Fatalf("wheel up must stop at the first group, cursor %d", m.cursor)
+	}
+
+	// A click on a name moves the cursor; a second click selects.
+	m = press(m, tea.MouseButtonLeft, 10, groupListTop+2)
+	if m.cursor != 2 || m.selectedCount() != 0 {
+		t.Fatalf("click: cursor %d, %d selected", m.cursor, m.selectedCount())
+	}
+	m = press(m, tea.MouseButtonLeft, 10, groupListTop+2)
+	if m.selectedCount() != 1 {
+		t.Fatalf("second click must select the group")
+	}
+	// A click on a checkbox selects right away.
+	m = press(m, tea.MouseButtonLeft, 3, groupListTop+4)
+	if m.cursor != 4 || m.selectedCount() != 2 {
+		t.Fatalf("checkbox click: cursor %d, %d selected", m.cursor, m.selectedCount())
+	}
+	// Rows below the last group do nothing.
+	m = press(m, tea.MouseButtonLeft, 10, groupListTop+10)
+	if m.cursor != 4 {
+		t.Fatalf("click below the list moved the cursor to %d", m.cursor)
+	}
+}
+
+func TestMouseIgnoredWhileCapturing(t *testing.T) {
+	m := mouseModel(t, 5)
+	m.searching = true
+	m = press(m, tea.MouseButtonWheelDown, 10, 5)
+	if m.cursor != 0 {
+		t.Fatalf("the mouse must be ignored while typing, cursor %d", m.cursor)
+	}
+}
+
+func TestGroupStartKeepsWindow(t *testing.T) {
+	m := mouseModel(t, 100)
+	rows := m.groupRows()
+	m.cursor = rows + 5
+	start := m.groupStart()
+	if start != 6 {
+		t.Fatalf("start %d, want 6 so the cursor is on the last row", start)
+	}
+	// Moving up inside the window keeps it where it is.
+	m.groupOffset = start
+	m.cursor = start + 1
+	if got := m.groupStart(); got != start {
+		t.Fatalf("start moved to %d, want %d", got, start)
+	}
+	m.cursor = 2
+	if got := m.groupStart(); got != 2 {
+		t.Fatalf("start %d, want 2 once the cursor leaves the top", got)
+	}
+}
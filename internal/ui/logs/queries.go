@@ -0,0 +1,460 @@
+package logs
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sachamama/sacha/internal/history"
+	"github.com/sachamama/sacha/internal/logs"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// queryRanges are the time ranges a saved query can be run over.
+var queryRanges = []time.Duration{15 * time.Minute, time.Hour, 24 * time.Hour, 7 * 24 * time.Hour}
+
+const (
+	defaultQueryRange = 1 // index into queryRanges
+	queryTimeout      = 5 * time.Minute
+	maxColumnWidth    = 60
+	queryRows         = 15
+)
+
+type queryMode int
+
+const (
+	queryList queryMode = iota
+	queryForm
+	queryRename
+	queryConfirmDelete
+	queryRunning
+	queryResults
+)
+
+// queriesPanel manages saved Logs Insights query definitions of the primary
+// source and shows query results as a table.
+type queriesPanel struct {
+	active   bool
+	mode     queryMode
+	loading  bool
+	defs     []logs.QueryDefinition
+	cursor   int
+	rangeIdx int
+
+	editing logs.QueryDefinition
+	name    textinput.Model
+	groups  textinput.Model
+	query   textarea.Model
+	focus   int
+
+	running logs.QueryDefinition
+	// source is the source the running query runs against.
+	source string
+	result logs.QueryResult
+	table  table.Model
+}
+
+type queryDefsLoadedMsg struct {
+	defs []logs.QueryDefinition
+	err  error
+}
+
+type queryChangedMsg struct {
+	action string
+	err    error
+}
+
+type queryResultMsg struct {
+	def    logs.QueryDefinition
+	result logs.QueryResult
+	err    error
+}
+
+func newQueriesPanel() queriesPanel {
+	name := textinput.New()
+	name.Prompt = "name: "
+	groups := textinput.New()
+	groups.Prompt = "log groups: "
+	groups.Placeholder = "space separated; empty uses the selected groups"
+	query := textarea.New()
+	query.Placeholder = "fields @timestamp, @message | sort @timestamp desc | limit 50"
+	query.ShowLineNumbers = false
+	return queriesPanel{name: name, groups: groups, query: query, rangeIdx: defaultQueryRange}
+}
+
+func (m *Model) openQueries() tea.Cmd {
+	if _, err := m.queryRunner(m.primary); err != nil {
+		m.statusLine = err.Error()
+		return nil
+	}
+	m.queries.active = true
+	m.queries.mode = queryList
+	m.queries.loading = true
+	return m.listQueriesCmd()
+}
+
+// queryRunner returns the source under key if it runs Logs Insights
+// queries. Saved definitions are those of the primary source.
+func (m Model) queryRunner(key string) (logs.QueryRunner, error) {
+	r, ok := m.sources[key].client.(logs.QueryRunner)
+	if !ok {
+		return nil, unsupported("queries")
+	}
+	return r, nil
+}
+
+func (m Model) listQueriesCmd() tea.Cmd {
+	client, err := m.queryRunner(m.primary)
+	return func() tea.Msg {
+		if err != nil {
+			return queryDefsLoadedMsg{err: err}
+		}
+		defs, err := client.ListQueryDefinitions(context.Background())
+		return queryDefsLoadedMsg{defs: defs, err: err}
+	}
+}
+
+func (m Model) saveQueryCmd(def logs.QueryDefinition, action string) tea.Cmd {
+	client, err := m.queryRunner(m.primary)
+	return func() tea.Msg {
+		if err != nil {
+			return queryChangedMsg{err: err}
+		}
+		_, err := client.SaveQueryDefinition(context.Background(), def)
+		return queryChangedMsg{action: action, err: err}
+	}
+}
+
+func (m Model) deleteQueryCmd(def logs.QueryDefinition) tea.Cmd {
+	client, err := m.queryRunner(m.primary)
+	return func() tea.Msg {
+		if err != nil {
+			return queryChangedMsg{err: err}
+		}
+		err := client.DeleteQueryDefinition(context.Background(), def.ID)
+		return queryChangedMsg{action: "deleted " + def.Name, err: err}
+	}
+}
+
+// runQueryCmd runs def against the source under key.
+func (m Model) runQueryCmd(key string, def logs.QueryDefinition) tea.Cmd {
+	client, err := m.queryRunner(key)
+	groups := def.LogGroups
+	if len(groups) == 0 {
+		groups = m.selectedBySource()[key]
+	}
+	end := time.Now()
+	start := end.Add(-queryRanges[m.queries.rangeIdx])
+	return func() tea.Msg {
+		if err != nil {
+			return queryResultMsg{def: def, err: err}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+		defer cancel()
+		res, err := client.RunQuery(ctx, groups, def.Query, start, end)
+		return queryResultMsg{def: def, result: res, err: err}
+	}
+}
+
+// updateQueriesMsg handles async results for the panel.
+func (m *Model) updateQueriesMsg(msg tea.Msg) tea.Cmd {
+	q := &m.queries
+	switch msg := msg.(type) {
+	case queryDefsLoadedMsg:
+		q.loading = false
+		if msg.err != nil {
+			m.statusLine = msg.err.Error()
+			return nil
+		}
+		q.defs = msg.defs
+		if q.cursor >= len(q.defs) {
+			q.cursor = max(len(q.defs)-1, 0)
+		}
+	case queryChangedMsg:
+		if msg.err != nil {
+			m.statusLine = msg.err.Error()
+			return nil
+		}
+		m.statusLine = "query " + msg.action
+		q.loading = true
+		return m.listQueriesCmd()
+	case queryResultMsg:
+		if q.mode != queryRunning || msg.def.ID != q.running.ID {
+			return nil
+		}
+		if msg.err != nil {
+			q.mode = queryList
+			m.statusLine = msg.err.Error()
+			return nil
+		}
+		q.result = msg.result
+		q.table = resultTable(msg.result, m.bodyHeight()-6)
+		q.mode = queryResults
+	}
+	return nil
+}
+
+func (m Model) updateQueries(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	q := &m.queries
+	switch q.mode {
+	case queryForm:
+		return m.updateQueryForm(msg)
+	case queryRename:
+		switch msg.Type {
+		case tea.KeyEscape:
+			q.mode = queryList
+			return m, nil
+		case tea.KeyEnter:
+			q.mode = queryList
+			def := q.editing
+			def.Name = strings.TrimSpace(q.name.Value())
+			if def.Name == "" {
+				return m, nil
+			}
+			return m, m.saveQueryCmd(def, "renamed to "+def.Name)
+		}
+		var cmd tea.Cmd
+		q.name, cmd = q.name.Update(msg)
+		return m, cmd
+	case queryConfirmDelete:
+		q.mode = queryList
+		if msg.String() == "y" {
+			return m, m.deleteQueryCmd(q.editing)
+		}
+		return m, nil
+	case queryRunning:
+		if msg.String() == "esc" {
+			q.mode = queryList
+		}
+		return m, nil
+	case queryResults:
+		switch msg.String() {
+		case "esc", "q":
+			q.mode = queryList
+			return m, nil
+		case "y":
+			return m, m.queryLinkCmd(q.source, q.running)
+		}
+		var cmd tea.Cmd
+		q.table, cmd = q.table.Update(msg)
+		return m, cmd
+	}
+
+	switch msg.String() {
+	case "esc", "q":
+		q.active = false
+	case "up", "k":
+		if q.cursor > 0 {
+			q.cursor--
+		}
+	case "down", "j":
+		if q.cursor < len(q.defs)-1 {
+			q.cursor++
+		}
+	case "[":
+		if q.rangeIdx > 0 {
+			q.rangeIdx--
+		}
+	case "]":
+		if q.rangeIdx < len(queryRanges)-1 {
+			q.rangeIdx++
+		}
+	case "g":
+		q.loading = true
+		return m, m.listQueriesCmd()
+	case "n":
+		return m, m.openQueryForm(logs.QueryDefinition{LogGroups: m.selectedBySource()[m.primary]})
+	}
+
+	def, ok := q.current()
+	if !ok {
+		return m, nil
+	}
+	switch msg.String() {
+	case "enter":
+		q.running = def
+		q.source = m.primary
+		q.mode = queryRunning
+		return m, tea.Batch(m.runQueryCmd(m.primary, def), m.remember(history.KindQuery, def.Query, def.LogGroups))
+	case "y":
+		return m, m.queryLinkCmd(m.primary, def)
+	case "e":
+		return m, m.openQueryForm(def)
+	case "r":
+		q.editing = def
+		q.mode = queryRename
+		q.name.SetValue(def.Name)
+		q.name.CursorEnd()
+		return m, q.name.Focus()
+	case "d":
+		q.editing = def
+		q.mode = queryConfirmDelete
+	}
+	return m, nil
+}
+
+func (q queriesPanel) current() (logs.QueryDefinition, bool) {
+	if q.cursor >= len(q.defs) {
+		return logs.QueryDefinition{}, false
+	}
+	return q.defs[q.cursor], true
+}
+
+func (m *Model) openQueryForm(def logs.QueryDefinition) tea.Cmd {
+	q := &m.queries
+	q.editing = def
+	q.mode = queryForm
+	q.name.SetValue(def.Name)
+	q.groups.SetValue(strings.Join(def.LogGroups, " "))
+	q.query.SetValue(def.Query)
+	q.query.SetWidth(max(m.width-8, 20))
+	q.query.SetHeight(max(m.bodyHeight()-10, 3))
+	q.focus = 0
+	return q.focusField()
+}
+
+func (q *queriesPanel) focusField() tea.Cmd {
+	q.name.Blur()
+	q.groups.Blur()
+	q.query.Blur()
+	switch q.focus {
+	case 1:
+		return q.groups.Focus()
+	case 2:
+		return q.query.Focus()
+	}
+	return q.name.Focus()
+}
+
+func (m Model) updateQueryForm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	q := &m.queries
+	switch msg.String() {
+	case "esc":
+		q.mode = queryList
+		return m, nil
+	case "tab", "shift+tab":
+		if msg.String() == "tab" {
+			q.focus = (q.focus + 1) % 3
+		} else {
+			q.focus = (q.focus + 2) % 3
+		}
+		return m, q.focusField()
+	case "ctrl+s":
+		def := q.editing
+		def.Name = strings.TrimSpace(q.name.Value())
+		def.LogGroups = strings.Fields(q.groups.Value())
+		def.Query = strings.TrimSpace(q.query.Value())
+		if def.Name == "" || def.Query == "" {
+			m.statusLine = "a query needs a name and a query string"
+			return m, nil
+		}
+		q.mode = queryList
+		action := "updated"
+		if def.ID == "" {
+			action = "created"
+		}
+		return m, m.saveQueryCmd(def, action+" "+def.Name)
+	}
+	var cmd tea.Cmd
+	switch q.focus {
+	case 0:
+		q.name, cmd = q.name.Update(msg)
+	case 1:
+		q.groups, cmd = q.groups.Update(msg)
+	default:
+		q.query, cmd = q.query.Update(msg)
+	}
+	return m, cmd
+}
+
+func resultTable(res logs.QueryResult, height int) table.Model {
+	cols := make([]table.Column, len(res.Fields))
+	for i, f := range res.Fields {
+		width := len(f)
+		for _, row := range res.Rows {
+			width = max(width, len(row[i]))
+		}
+		cols[i] = table.Column{Title: f, Width: min(width, maxColumnWidth)}
+	}
+	rows := make([]table.Row, len(res.Rows))
+	for i, row := range res.Rows {
+		r := make(table.Row, len(row))
+		for j, v := range row {
+			r[j] = strings.Join(strings.Fields(v), " ")
+		}
+		rows[i] = r
+	}
+	return table.New(
+		table.WithColumns(cols),
+		table.WithRows(rows),
+		table.WithFocused(true),
+		table.WithHeight(max(height, 3)),
+	)
+}
+
+func (m Model) renderQueries() string {
+	q := m.queries
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s\n", m.styles.title.Render("Saved Logs Insights queries"), m.styles.dim.Render(m.primary))
+	switch q.mode {
+	case queryForm:
+		title := "New query"
+		if q.editing.ID != "" {
+			title = "Edit " + q.editing.Name
+		}
+		fmt.Fprintln(&b, m.styles.dim.Render(title+" (tab next field, ctrl+s save, esc cancel)"))
+		fmt.Fprintln(&b, q.name.View())
+		fmt.Fprintln(&b, q.groups.View())
+		fmt.Fprintln(&b, q.query.View())
+		return b.String()
+	case queryRunning:
+		fmt.Fprintf(&b, "running %q over the last %s... (esc to leave)\n", q.running.Name, queryRanges[q.rangeIdx])
+		return b.String()
+	case queryResults:
+		fmt.Fprintln(&b, m.styles.dim.Render(fmt.Sprintf("%s: %d rows, %.0f records matched, %.1f MB scanned (y console link, esc back)",
+			q.running.Name, len(q.result.Rows), q.result.RecordsMatched, q.result.BytesScanned/1e6)))
+		fmt.Fprintln(&b, q.table.View())
+		return b.String()
+	}
+
+	help := fmt.Sprintf("range %s ([ ]) | enter run, n new, e edit, r rename, d delete, y console link, g reload, esc close", queryRanges[q.rangeIdx])
+	fmt.Fprintln(&b, m.styles.dim.Render(help))
+	switch {
+	case q.mode == queryRename:
+		fmt.Fprintln(&b, q.name.View())
+	case q.mode == queryConfirmDelete:
+		fmt.Fprintln(&b, m.styles.warn.Render(fmt.Sprintf("delete %q? y/n", q.editing.Name)))
+	case q.loading:
+		fmt.Fprintln(&b, m.styles.dim.Render("loading..."))
+	case len(q.defs) == 0:
+		fmt.Fprintln(&b, "no saved queries")
+	}
+	start := 0
+	if q.cursor >= queryRows {
+		start = q.cursor - queryRows + 1
+	}
+	for i := start; i < len(q.defs) && i < start+queryRows; i++ {
+		def := q.defs[i]
+		groups := strings.Join(def.LogGroups, ", ")
+		if groups == "" {
+			groups = "(selected groups)"
+		}
+		line := fmt.Sprintf("%-30s %s", def.Name, m.styles.dim.Render(groups))
+		if i == q.cursor {
+			line = m.styles.cursor.Render(fmt.Sprintf("%-30s %s", def.Name, groups))
+		}
+		fmt.Fprintln(&b, line)
+	}
+	if def, ok := q.current(); ok {
+		fmt.Fprintf(&b, "\n%s\n", m.styles.dim.Render(def.Query))
+	}
+	if m.statusLine != "" {
+		fmt.Fprintf(&b, "\n%s\n", m.styles.status.Render(m.statusLine))
+	}
+	return b.String()
+}
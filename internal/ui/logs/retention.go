@@ -0,0 +1,120 @@
+package logs
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/sachamama/sacha/internal/logs"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// retentionConfirm asks before changing a log group's retention: a shorter
+// retention deletes older events for good.
+type retentionConfirm struct {
+	active  bool
+	source  string
+	group   string
+	current int32 // 0 means events never expire
+	days    int32
+	client  logs.RetentionSetter
+}
+
+type retentionSetMsg struct {
+	source string
+	group  string
+	days   int32
+	err    error
+}
+
+// setRetention asks to change the retention of the log group under the
+// cursor.
+func (m Model) setRetention(args string) (tea.Model, tea.Cmd) {
+	days, err := strconv.Atoi(args)
+	if err != nil {
+		m.statusLine = fmt.Sprintf("retention: %q is not a number of days", args)
+		return m, nil
+	}
+	if !slices.Contains(logs.RetentionDays, int32(days)) {
+		m.statusLine = fmt.Sprintf("retention: %d days is not supported, use one of %v", days, logs.RetentionDays)
+		return m, nil
+	}
+	groups := m.filteredGroups()
+	if m.cursor >= len(groups) {
+		m.statusLine = "retention: no log group under the cursor"
+		return m, nil
+	}
+	g := groups[m.cursor]
+	key := refOf(g).source
+	client, ok := m.sources[key].client.(logs.RetentionSetter)
+	if !ok {
+		m.statusLine = unsupported("retention").Error()
+		return m, nil
+	}
+	m.retention = retentionConfirm{
+		active:  true,
+		source:  key,
+		group:   g.Name,
+		current: g.RetentionDays,
+		days:    int32(days),
+		client:  client,
+	}
+	return m, nil
+}
+
+func (m Model) updateRetention(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	r := m.retention
+	m.retention = retentionConfirm{}
+	if msg.String() != "y" {
+		m.statusLine = "retention of " + r.group + " unchanged"
+		return m, nil
+	}
+	return m, func() tea.Msg {
+		err := r.client.SetRetention(context.Background(), r.group, r.days)
+		return retentionSetMsg{source: r.source, group: r.group, days: r.days, err: err}
+	}
+}
+
+// deletes reports whether the new retention drops events the group keeps
+// today.
+func (r retentionConfirm) deletes() bool {
+	return r.current == 0 || r.days < r.current
+}
+
+func (m Model) renderRetention() string {
+	r := m.retention
+	var b strings.Builder
+	fmt.Fprintln(&b, m.styles.title.Render("Change retention"))
+	fmt.Fprintf(&b, "\nlog group  %s\n", r.group)
+	fmt.Fprintf(&b, "source     %s\n", r.source)
+	fmt.Fprintf(&b, "current    %s\n", retentionLabel(r.current))
+	fmt.Fprintf(&b, "new        %s\n\n", retentionLabel(r.days))
+	if r.deletes() {
+		fmt.Fprintln(&b, m.styles.warn.Render(fmt.Sprintf("Events older than %d days will be deleted permanently.", r.days)))
+	}
+	fmt.Fprintln(&b, m.styles.warn.Render("apply? y/n"))
+	return b.String()
+}
+
+func retentionLabel(days int32) string {
+	if days == 0 {
+		return "never expire"
+	}
+	return fmt.Sprintf("%d days", days)
+}
+
+func (m *Model) retentionSet(msg retentionSetMsg) {
+	if msg.err != nil {
+		m.statusLine = msg.err.Error()
+		return
+	}
+	for i, g := range m.logGroups {
+		if g.Name == msg.group && refOf(g).source == msg.source {
+			m.logGroups[i].RetentionDays = msg.days
+		}
+	}
+	m.statusLine = fmt.Sprintf("%s keeps events for %d days", msg.group, msg.days)
+}
@@ -6,6 +6,7 @@ import (
 
 	sdkaws "github.com/aws/aws-sdk-go-v2/aws"
 	awsx "github.com/sachamama/sacha/internal/aws"
+	"github.com/sachamama/sacha/internal/demo"
 	"github.com/sachamama/sacha/internal/logs"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -27,7 +28,16 @@ func (CloudWatchLogsService) Init(ctx context.Context, cfg sdkaws.Config, opts a
 	if cfg.Region == "" {
 		return nil, fmt.Errorf("region must be set before loading CloudWatch Logs")
 	}
-	client := logs.NewClient(cfg)
-	model := NewModel(client)
+	client := newClient(opts.Profile, cfg, opts.Demo)
+	model := NewModel(cfg.Region, client, opts)
+	model.resolveAccount = model.accountCmd(cfg)
 	return model, nil
 }
+
+// newClient talks to CloudWatch Logs, or to the in-memory fake in demo mode.
+func newClient(profile string, cfg sdkaws.Config, demoMode bool) *logs.Client {
+	if demoMode {
+		return logs.NewClientFromAPI(demo.For(profile, cfg.Region))
+	}
+	return logs.NewClient(cfg)
+}
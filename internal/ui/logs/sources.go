@@ -0,0 +1,289 @@
+package logs
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sachamama/sacha/internal/logs"
+
+	sdkaws "github.com/aws/aws-sdk-go-v2/aws"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// source is one CloudWatch Logs endpoint, a profile/region pair, or the
+// local files, contributing groups to the model. Each source has its own
+// credentials, so a failing profile only affects its own groups.
+type source struct {
+	profile string
+	region  string
+	client  logs.Source
+	// account is the AWS account of the credentials, resolved once when the
+	// source is added; it is empty for files or if the lookup failed.
+	account string
+}
+
+// replay reports whether tailing starts at the beginning of the source
+// instead of the tail window, as for saved log files.
+func (s source) replay() bool {
+	_, ok := s.client.(*logs.FileSource)
+	return ok
+}
+
+func (s source) key() string {
+	return sourceKey(s.profile, s.region)
+}
+
+func sourceKey(profile, region string) string {
+	if profile == "" {
+		return region
+	}
+	return profile + "@" + region
+}
+
+// sourceLabel is shown next to groups and events. It names the account too,
+// since two profiles may reach the same account or a profile may change
+// accounts.
+func (m Model) sourceLabel(profile, region string) string {
+	key := sourceKey(profile, region)
+	if account := m.sources[key].account; account != "" {
+		return key + " (" + account + ")"
+	}
+	return key
+}
+
+func (s source) tagGroups(groups []logs.LogGroup) {
+	for i := range groups {
+		groups[i].Region = s.region
+		groups[i].Profile = s.profile
+	}
+}
+
+func (s source) tagEvents(events []logs.TailEvent) {
+	for i := range events {
+		events[i].Region = s.region
+		events[i].Profile = s.profile
+	}
+}
+
+// groupRef identifies a log group within a source.
+type groupRef struct {
+	source string
+	name   string
+}
+
+func refOf(g logs.LogGroup) groupRef {
+	return groupRef{source: sourceKey(g.Profile, g.Region), name: g.Name}
+}
+
+type sourcePrompt int
+
+const (
+	promptNone sourcePrompt = iota
+	promptRegion
+	promptProfile
+	promptFile
+	promptExport
+	promptTimeline
+)
+
+type sourceAddedMsg struct {
+	source source
+	err    error
+}
+
+type accountResolvedMsg struct {
+	source  string
+	account string
+}
+
+// accountCmd resolves the account of the primary source, whose config is
+// loaded by the app.
+func (m Model) accountCmd(cfg sdkaws.Config) tea.Cmd {
+	loader, key, profile := m.loader, m.primary, m.profile
+	return func() tea.Msg {
+		account, _ := loader.AccountID(context.Background(), profile, cfg)
+		return accountResolvedMsg{source: key, account: account}
+	}
+}
+
+func (m *Model) accountResolved(msg accountResolvedMsg) {
+	if src, ok := m.sources[msg.source]; ok {
+		src.account = msg.account
+		m.sources[msg.source] = src
+	}
+}
+
+func (m Model) addSourceCmd(profile, region string) tea.Cmd {
+	loader, demoMode := m.loader, m.demo
+	return func() tea.Msg {
+		cfg, err := loader.Load(context.Background(), profile, region)
+		if err != nil {
+			return sourceAddedMsg{err: fmt.Errorf("add %s: %w", sourceKey(profile, region), err)}
+		}
+		if region == "" {
+			region = cfg.Region
+		}
+		if region == "" {
+			return sourceAddedMsg{err: fmt.Errorf("add %s: no region configured", profile)}
+		}
+		// Labels fall back to the profile if the account cannot be resolved.
+		account, _ := loader.AccountID(context.Background(), profile, cfg)
+		return sourceAddedMsg{source: source{profile: profile, region: region, client: newClient(profile, cfg, demoMode), account: account}}
+	}
+}
+
+func (m *Model) openSourcePrompt(kind sourcePrompt) tea.Cmd {
+	m.prompt = kind
+	switch kind {
+	case promptRegion:
+		m.sourceInput.Placeholder = "region, e.g. eu-west-1"
+		m.sourceInput.Prompt = "+ "
+	case promptProfile:
+		m.sourceInput.Placeholder = "profile [region], e.g. prod us-east-1"
+		m.sourceInput.Prompt = "@ "
+	case promptFile:
+		m.sourceInput.Placeholder = "path to a log file, NDJSON export or filter-log-events JSON"
+		m.sourceInput.Prompt = "open: "
+	case promptExport:
+		m.sourceInput.Placeholder = "file to write"
+		m.sourceInput.Prompt = "export: "
+	case promptTimeline:
+		m.sourceInput.Placeholder = "Markdown file to write"
+		m.sourceInput.Prompt = "timeline: "
+	}
+	m.sourceInput.SetValue("")
+	switch kind {
+	case promptExport:
+		m.sourceInput.SetValue(time.Now().Format("sacha-20060102-150405.ndjson"))
+		m.sourceInput.CursorEnd()
+	case promptTimeline:
+		m.sourceInput.SetValue(time.Now().Format("incident-20060102-150405.md"))
+		m.sourceInput.CursorEnd()
+	}
+	return m.sourceInput.Focus()
+}
+
+func (m Model) updateSourcePrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEscape:
+		m.prompt = promptNone
+		m.sourceInput.Blur()
+		return m, nil
+	case tea.KeyEnter:
+		kind := m.prompt
+		m.prompt = promptNone
+		m.sourceInput.Blur()
+		switch value := strings.TrimSpace(m.sourceInput.Value()); {
+		case value == "":
+			return m, nil
+		case kind == promptFile:
+			m.statusLine = "opening " + value + "..."
+			return m, m.openFileCmd(value)
+		case kind == promptExport:
+			return m, exportEventsCmd(value, m.visibleEvents())
+		case kind == promptTimeline:
+			return m, m.writeTimelineCmd(value)
+		}
+		fields := strings.Fields(m.sourceInput.Value())
+		profile, region := m.profile, fields[0]
+		if kind == promptProfile {
+			profile, region = fields[0], m.primaryRegion()
+			if len(fields) > 1 {
+				region = fields[1]
+			}
+		}
+		key := sourceKey(profile, region)
+		if _, ok := m.sources[key]; ok {
+			m.statusLine = fmt.Sprintf("%s already loaded", key)
+			return m, nil
+		}
+		m.statusLine = fmt.Sprintf("loading log groups from %s...", key)
+		return m, m.addSourceCmd(profile, region)
+	}
+	var cmd tea.Cmd
+	m.sourceInput, cmd = m.sourceInput.Update(msg)
+	return m, cmd
+}
+
+func (m Model) primaryRegion() string {
+	return m.sources[m.primary].region
+}
+
+func (m *Model) addSource(src source) {
+	if _, ok := m.sources[src.key()]; !ok {
+		m.sourceOrder = append(m.sourceOrder, src.key())
+	}
+	m.sources[src.key()] = src
+}
+
+// removeSource drops the source owning the group under the cursor. The
+// primary source cannot be removed; switch regions from the app instead.
+func (m *Model) removeSource() {
+	groups := m.filteredGroups()
+	if len(groups) == 0 || m.cursor >= len(groups) {
+		return
+	}
+	ref := refOf(groups[m.cursor])
+	key := ref.source
+	if key == m.primary {
+		m.statusLine = "cannot remove the primary source"
+		return
+	}
+	if key == localSource && m.files.Len() > 1 {
+		m.closeFile(ref.name)
+		return
+	}
+	m.dropSource(key)
+	if m.cursor >= len(m.filteredGroups()) && m.cursor > 0 {
+		m.cursor--
+	}
+	m.statusLine = fmt.Sprintf("removed %s", key)
+}
+
+func (m *Model) dropSource(key string) {
+	if key == localSource {
+		m.files = logs.NewFileSource()
+	}
+	delete(m.sources, key)
+	delete(m.tailStarts, key)
+	for i, k := range m.sourceOrder {
+		if k == key {
+			m.sourceOrder = append(m.sourceOrder[:i], m.sourceOrder[i+1:]...)
+			break
+		}
+	}
+	for ref := range m.selected {
+		if ref.source == key {
+			delete(m.selected, ref)
+		}
+	}
+	m.replaceGroups(key, nil)
+}
+
+// replaceGroups swaps the groups of one source, keeping sources in the order
+// they were added.
+func (m *Model) replaceGroups(key string, groups []logs.LogGroup) {
+	bySource := map[string][]logs.LogGroup{key: groups}
+	for _, g := range m.logGroups {
+		if k := refOf(g).source; k != key {
+			bySource[k] = append(bySource[k], g)
+		}
+	}
+	all := make([]logs.LogGroup, 0, len(m.logGroups)+len(groups))
+	for _, k := range m.sourceOrder {
+		all = append(all, bySource[k]...)
+	}
+	m.logGroups = all
+}
+
+func (m Model) multiSource() bool {
+	return len(m.sources) > 1
+}
+
+// unsupported reports an operation the source under it cannot perform, such
+// as writing to a local file.
+func unsupported(what string) error {
+	return fmt.Errorf("%s: not supported for this source", what)
+}
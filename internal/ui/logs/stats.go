@@ -0,0 +1,173 @@
+package logs
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sachamama/sacha/internal/history"
+	"github.com/sachamama/sacha/internal/logs"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+const statsTopN = 20
+
+// statsOverlay shows the most frequent values of a JSON field over the tail
+// buffer. It recomputes on every render, so counts follow the live tail.
+type statsOverlay struct {
+	active  bool
+	editing bool
+	input   textinput.Model
+	path    logs.FieldPath
+	cursor  int
+}
+
+func newStatsOverlay() statsOverlay {
+	in := textinput.New()
+	in.Placeholder = "$.statusCode"
+	in.Prompt = "field: "
+	return statsOverlay{input: in}
+}
+
+func (s *statsOverlay) open() tea.Cmd {
+	s.active = true
+	s.editing = true
+	s.cursor = 0
+	return s.input.Focus()
+}
+
+func (m Model) updateStats(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	s := &m.stats
+	if s.editing {
+		switch msg.Type {
+		case tea.KeyEscape:
+			s.editing = false
+			s.input.Blur()
+			if s.path.String() == "" {
+				s.active = false
+			}
+			return m, nil
+		case tea.KeyEnter:
+			path, err := logs.ParseFieldPath(s.input.Value())
+			if err != nil {
+				m.statusLine = err.Error()
+				return m, nil
+			}
+			s.path = path
+			s.cursor = 0
+			s.editing = false
+			s.input.Blur()
+			return m, nil
+		}
+		var cmd tea.Cmd
+		s.input, cmd = s.input.Update(msg)
+		return m, cmd
+	}
+
+	values := logs.TopValues(m.events, s.path, statsTopN).Values
+	switch msg.String() {
+	case "esc", "q":
+		s.active = false
+	case "up", "k":
+		if s.cursor > 0 {
+			s.cursor--
+		}
+	case "down", "j":
+		if s.cursor < len(values)-1 {
+			s.cursor++
+		}
+	case "e", "/":
+		s.editing = true
+		return m, s.input.Focus()
+	case "enter":
+		if s.cursor < len(values) {
+			m.setFilter(logs.FieldFilter(s.path, values[s.cursor].Value))
+			s.active = false
+		}
+	}
+	return m, nil
+}
+
+func (m Model) renderStats() string {
+	s := m.stats
+	var b strings.Builder
+	fmt.Fprintln(&b, m.styles.title.Render("Field values"))
+	if s.editing || s.path.String() == "" {
+		fmt.Fprintln(&b, s.input.View())
+		fmt.Fprintln(&b, m.styles.dim.Render("enter a JSON path, e.g. $.statusCode or $.user.id"))
+		return b.String()
+	}
+	stats := logs.TopValues(m.events, s.path, statsTopN)
+	fmt.Fprintln(&b, m.styles.dim.Render(fmt.Sprintf("%s in %d of %d events (enter filter, e edit, esc close)", s.path, stats.Matched, stats.Total)))
+	if len(stats.Values) == 0 {
+		fmt.Fprintln(&b, "no values")
+		return b.String()
+	}
+	// Widths are in terminal cells, so non-ASCII values line up.
+	width := 0
+	for _, v := range stats.Values {
+		width = max(width, ansi.StringWidth(v.Value))
+	}
+	width = min(width, 40)
+	for i, v := range stats.Values {
+		value := ansi.Truncate(v.Value, width, "…")
+		value += strings.Repeat(" ", width-ansi.StringWidth(value))
+		line := fmt.Sprintf("%s %6d %5.1f%% %s", value, v.Count, v.Percent, bar(v.Percent, 20))
+		if i == s.cursor {
+			line = m.styles.cursor.Render(line)
+		}
+		fmt.Fprintln(&b, line)
+	}
+	return b.String()
+}
+
+func bar(percent float64, width int) string {
+	n := int(percent / 100 * float64(width))
+	return strings.Repeat("█", n)
+}
+
+func (m *Model) openFilterPrompt() tea.Cmd {
+	m.filtering = true
+	m.filterInput.SetValue(m.filter.String())
+	m.filterInput.CursorEnd()
+	m.filterRecall.reset(m.recallItems(history.KindTailFilter))
+	return m.filterInput.Focus()
+}
+
+func (m Model) updateFilterPrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEscape:
+		m.filtering = false
+		m.filterInput.Blur()
+		return m, nil
+	case tea.KeyEnter:
+		m.filtering = false
+		m.filterInput.Blur()
+		f, err := logs.ParseFilter(m.filterInput.Value())
+		if err != nil {
+			m.statusLine = err.Error()
+			return m, nil
+		}
+		m.setFilter(f)
+		return m, m.remember(history.KindTailFilter, f.String(), m.selectedNames())
+	}
+	if recallKey(&m.filterInput, &m.filterRecall, msg) {
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.filterInput, cmd = m.filterInput.Update(msg)
+	return m, cmd
+}
+
+func (m *Model) setFilter(f logs.Filter) {
+	m.filter = f
+	if f.Empty() {
+		m.statusLine = "tail filter cleared"
+	} else {
+		m.statusLine = "tail filter: " + f.String()
+	}
+	m.follow = true
+	m.refreshTail()
+}
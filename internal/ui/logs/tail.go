@@ -0,0 +1,126 @@
+package logs
+
+import (
+	"strings"
+
+	"github.com/sachamama/sacha/internal/logs"
+)
+
+// visibleEvents is the tail buffer after multi-line joining, the tail
+// filter and collapsing of repeated lines, each if enabled.
+func (m Model) visibleEvents() []logs.TailEvent {
+	events := m.events
+	if m.multiline {
+		events = logs.JoinMultiline(events, logs.DefaultJoinWindow)
+	}
+	events = m.filter.Apply(events)
+	if m.dedupe {
+		events = logs.CollapseRepeats(events)
+	}
+	return events
+}
+
+// toggleExpanded expands or collapses the event under the cursor.
+func (m *Model) toggleExpanded() {
+	e, ok := m.cursorEvent()
+	if !ok || !m.collapsible(e) {
+		return
+	}
+	key := eventKey(e)
+	if m.expanded[key] {
+		delete(m.expanded, key)
+	} else {
+		m.expanded[key] = true
+	}
+	m.refreshTail()
+}
+
+// collapsible reports whether an event renders on one line until expanded:
+// joined multi-line events and runs of repeated lines.
+func (m Model) collapsible(e logs.TailEvent) bool {
+	return len(e.Repeats) > 1 || m.multiline && strings.Contains(strings.TrimSpace(e.Message), "\n")
+}
+
+// toggleWrap switches the tail between wrapped rows and horizontal
+// scrolling.
+func (m *Model) toggleWrap() {
+	m.wrap = !m.wrap
+	m.view.SetXOffset(0)
+	m.statusLine = "line wrap on"
+	if !m.wrap {
+		m.statusLine = "line wrap off"
+		if h := m.keys.Hints("scroll-left", "left", "scroll-right", "right"); h != "" {
+			m.statusLine += " (" + h + ")"
+		}
+	}
+	m.refreshTail()
+}
+
+func eventKey(e logs.TailEvent) string {
+	return sourceKey(e.Profile, e.Region) + "|" + e.LogGroup + "|" + e.ID
+}
+
+// cursorIndex resolves the tail cursor to an index into visible. The cursor
+// follows the newest event until the user moves it.
+func (m Model) cursorIndex(visible []logs.TailEvent) int {
+	if len(visible) == 0 {
+		return -1
+	}
+	if !m.follow {
+		for i, e := range visible {
+			if eventKey(e) == m.cursorKey {
+				return i
+			}
+		}
+	}
+	return len(visible) - 1
+}
+
+// cursorEvent returns the event under the tail cursor.
+func (m Model) cursorEvent() (logs.TailEvent, bool) {
+	visible := m.visibleEvents()
+	idx := m.cursorIndex(visible)
+	if idx < 0 {
+		return logs.TailEvent{}, false
+	}
+	return visible[idx], true
+}
+
+// moveTailCursor moves the cursor by delta events; reaching the end resumes
+// following.
+func (m *Model) moveTailCursor(delta int) {
+	visible := m.visibleEvents()
+	if len(visible) == 0 {
+		return
+	}
+	idx := m.cursorIndex(visible) + delta
+	if idx < 0 {
+		idx = 0
+	}
+	if idx >= len(visible)-1 {
+		m.follow = true
+		m.cursorKey = ""
+	} else {
+		m.follow = false
+		m.cursorKey = eventKey(visible[idx])
+	}
+	m.refreshTail()
+}
+
+// refreshTail re-renders the buffer and scrolls the cursor into view.
+func (m *Model) refreshTail() {
+	visible := m.visibleEvents()
+	idx := m.cursorIndex(visible)
+	content, starts := m.renderEvents(visible, idx)
+	m.view.SetContent(content)
+	if idx < 0 {
+		m.view.GotoTop()
+		return
+	}
+	switch line := starts[idx]; {
+	case line < m.view.YOffset:
+		m.view.SetYOffset(line)
+	case line >= m.view.YOffset+m.view.Height:
+		m.view.SetYOffset(line - m.view.Height + 1)
+	}
+}
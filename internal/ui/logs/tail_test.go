@@ -0,0 +1,22 @@
+package logs
+
+import (
+	"testing"
+
+	awsx "github.com/sachamama/sacha/internal/aws"
+	"github.com/sachamama/sacha/internal/keymap"
+	"github.com/sachamama/sacha/internal/logs"
+)
+
+func TestToggleWrapHintsScrollKeys(t *testing.T) {
+	keys := keymap.MustNew(map[string][]string{"scroll-left": {"ctrl+b"}, "scroll-right": {"ctrl+f"}}, KeyGroups...)
+	m := NewModel("local", logs.NewFileSource(), awsx.ServiceOptions{Keys: keys})
+	m.toggleWrap()
+	if want := "line wrap on"; m.statusLine != want {
+		t.Fatalf("got %q, want %q", m.statusLine, want)
+	}
+	m.toggleWrap()
+	if want := "line wrap off (ctrl+b left, ctrl+f right)"; m.statusLine != want {
+		t.Fatalf("got %q, want %q", m.statusLine, want)
+	}
+}
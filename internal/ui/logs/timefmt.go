@@ -0,0 +1,104 @@
+package logs
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sachamama/sacha/internal/config"
+)
+
+// timeDisplay formats event timestamps according to the user's preference.
+type timeDisplay struct {
+	mode   string
+	zone   *time.Location
+	layout string
+}
+
+// newTimeDisplay reads the display preference from cfg. An unknown zone is
+// reported through the returned error; the zone mode then falls back to
+// local time, while the other modes still apply.
+func newTimeDisplay(cfg *config.Config) (timeDisplay, error) {
+	d := timeDisplay{mode: config.TimeDisplayLocal, layout: config.DefaultTimeFormat}
+	if cfg == nil {
+		return d, nil
+	}
+	if cfg.TimeFormat != "" {
+		d.layout = cfg.TimeFormat
+	}
+	var err error
+	if cfg.TimeZone != "" {
+		loc, zoneErr := time.LoadLocation(cfg.TimeZone)
+		if zoneErr != nil {
+			err = fmt.Errorf("time zone %q: %w", cfg.TimeZone, zoneErr)
+		}
+		d.zone = loc
+	}
+	switch cfg.TimeDisplay {
+	case config.TimeDisplayUTC, config.TimeDisplayRelative:
+		d.mode = cfg.TimeDisplay
+	case config.TimeDisplayZone:
+		if d.zone != nil {
+			d.mode = cfg.TimeDisplay
+		}
+	}
+	return d, err
+}
+
+// next cycles utc -> local -> zone -> relative, skipping zone when none is
+// configured.
+func (d timeDisplay) next() timeDisplay {
+	switch d.mode {
+	case config.TimeDisplayUTC:
+		d.mode = config.TimeDisplayLocal
+	case config.TimeDisplayLocal:
+		if d.zone != nil {
+			d.mode = config.TimeDisplayZone
+		} else {
+			d.mode = config.TimeDisplayRelative
+		}
+	case config.TimeDisplayZone:
+		d.mode = config.TimeDisplayRelative
+	default:
+		d.mode = config.TimeDisplayUTC
+	}
+	return d
+}
+
+func (d timeDisplay) label() string {
+	switch d.mode {
+	case config.TimeDisplayZone:
+		return d.zone.String()
+	case config.TimeDisplayRelative:
+		return "relative"
+	case config.TimeDisplayUTC:
+		return "UTC"
+	}
+	return "local"
+}
+
+func (d timeDisplay) format(t, now time.Time) string {
+	switch d.mode {
+	case config.TimeDisplayUTC:
+		return t.UTC().Format(d.layout)
+	case config.TimeDisplayZone:
+		return t.In(d.zone).Format(d.layout)
+	case config.TimeDisplayRelative:
+		return relativeTime(now.Sub(t))
+	}
+	return t.Local().Format(d.layout)
+}
+
+func relativeTime(age time.Duration) string {
+	if age < 0 {
+		age = 0
+	}
+	switch {
+	case age < time.Minute:
+		return fmt.Sprintf("%ds ago", int(age/time.Second))
+	case age < time.Hour:
+		return fmt.Sprintf("%dm ago", int(age/time.Minute))
+	case age < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(age/time.Hour))
+	}
+	return fmt.Sprintf("%dd ago", int(age/(24*time.Hour)))
+}
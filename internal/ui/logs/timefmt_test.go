@@ -0,0 +1,85 @@
+package logs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sachamama/sacha/internal/config"
+)
+
+func TestNewTimeDisplay(t *testing.T) {
+	cases := []struct {
+		name    string
+		cfg     *config.Config
+		mode    string
+		zone    bool
+		wantErr bool
+	}{
+		{"nil config", nil, config.TimeDisplayLocal, false, false},
+		{"default", &config.Config{}, config.TimeDisplayLocal, false, false},
+		{"utc", &config.Config{TimeDisplay: "utc"}, config.TimeDisplayUTC, false, false},
+		{"relative", &config.Config{TimeDisplay: "relative"}, config.TimeDisplayRelative, false, false},
+		{"zone", &config.Config{TimeDisplay: "zone", TimeZone: "Europe/Berlin"}, config.TimeDisplayZone, true, false},
+		{"zone without a zone", &config.Config{TimeDisplay: "zone"}, config.TimeDisplayLocal, false, false},
+		{"bad zone falls back to local", &config.Config{TimeDisplay: "zone", TimeZone: "Mars/Olympus"}, config.TimeDisplayLocal, false, true},
+		{"bad zone keeps utc", &config.Config{TimeDisplay: "utc", TimeZone: "Mars/Olympus"}, config.TimeDisplayUTC, false, true},
+		{"bad zone keeps relative", &config.Config{TimeDisplay: "relative", TimeZone: "Mars/Olympus"}, config.TimeDisplayRelative, false, true},
+	}
+	for _, tc := range cases {
+		d, err := newTimeDisplay(tc.cfg)
+		if (err != nil) != tc.wantErr {
+			t.Fatalf("%s: err = %v, want error %v", tc.name, err, tc.wantErr)
+		}
+		if d.mode != tc.mode || (d.zone != nil) != tc.zone {
+			t.Fatalf("%s: got mode %q zone %v, want %q zone %v", tc.name, d.mode, d.zone, tc.mode, tc.zone)
+		}
+	}
+}
+
+func TestTimeDisplayNext(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("no zone database: %v", err)
+	}
+	cases := []struct {
+		zone *time.Location
+		want []string
+	}{
+		{nil, []string{"local", "relative", "utc", "local"}},
+		{berlin, []string{"local", "zone", "relative", "utc", "local"}},
+	}
+	for _, tc := range cases {
+		d := timeDisplay{mode: config.TimeDisplayUTC, zone: tc.zone}
+		for _, want := range tc.want {
+			d = d.next()
+			if d.mode != want {
+				t.Fatalf("zone %v: got %q, want %q", tc.zone, d.mode, want)
+			}
+		}
+	}
+}
+
+func TestTimeDisplayFormat(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("no zone database: %v", err)
+	}
+	at := time.Date(2024, 5, 1, 12, 30, 45, 123456789, time.UTC)
+	cases := []struct {
+		d    timeDisplay
+		now  time.Time
+		want string
+	}{
+		{timeDisplay{mode: config.TimeDisplayUTC, layout: config.DefaultTimeFormat}, at, "2024-05-01T12:30:45.123Z"},
+		{timeDisplay{mode: config.TimeDisplayZone, zone: berlin, layout: config.DefaultTimeFormat}, at, "2024-05-01T14:30:45.123+02:00"},
+		{timeDisplay{mode: config.TimeDisplayUTC, layout: "15:04:05"}, at, "12:30:45"},
+		{timeDisplay{mode: config.TimeDisplayRelative}, at.Add(90 * time.Second), "1m ago"},
+		{timeDisplay{mode: config.TimeDisplayRelative}, at.Add(-time.Second), "0s ago"},
+		{timeDisplay{mode: config.TimeDisplayRelative}, at.Add(50 * time.Hour), "2d ago"},
+	}
+	for _, tc := range cases {
+		if got := tc.d.format(at, tc.now); got != tc.want {
+			t.Fatalf("%s: got %q, want %q", tc.d.mode, got, tc.want)
+		}
+	}
+}
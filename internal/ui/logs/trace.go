@@ -0,0 +1,231 @@
+package logs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sachamama/sacha/internal/logs"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// correlationWindow is searched on both sides of the followed event.
+	correlationWindow = 15 * time.Minute
+	// searchTimeout bounds a search across every source.
+	searchTimeout = 30 * time.Second
+)
+
+// traceView shows every event sharing a correlation ID as one timeline.
+type traceView struct {
+	active  bool
+	choices []logs.CorrelationID // set while the user picks among several IDs
+	cursor  int
+	origin  logs.TailEvent
+	id      logs.CorrelationID
+	loading bool
+	events  []logs.TailEvent
+	groups  int
+	// truncated is set when a source stopped at its page limit or the
+	// search timed out.
+	truncated bool
+	view      viewport.Model
+}
+
+type traceLoadedMsg struct {
+	id        logs.CorrelationID
+	events    []logs.TailEvent
+	truncated bool
+	err       error
+}
+
+// correlationFields returns the configured JSON paths, or the defaults.
+func (m Model) correlationFields() []logs.FieldPath {
+	exprs := m.settings.CorrelationFields
+	if len(exprs) == 0 {
+		exprs = logs.DefaultCorrelationFields
+	}
+	paths := make([]logs.FieldPath, 0, len(exprs))
+	for _, expr := range exprs {
+		if p, err := logs.ParseFieldPath(expr); err == nil {
+			paths = append(paths, p)
+		}
+	}
+	return paths
+}
+
+// startTrace detects IDs in the event under the cursor and follows the only
+// one, or asks the user to pick.
+func (m Model) startTrace() (tea.Model, tea.Cmd) {
+	e, ok := m.cursorEvent()
+	if !ok {
+		return m, nil
+	}
+	ids := logs.FindCorrelationIDs(e.Message, m.correlationFields())
+	if len(ids) == 0 {
+		m.statusLine = "no request or trace ID in this event"
+		return m, nil
+	}
+	m.trace = traceView{active: true, origin: e, view: viewport.New(0, 0)}
+	if len(ids) > 1 {
+		m.trace.choices = ids
+		return m, nil
+	}
+	return m.followID(ids[0])
+}
+
+func (m Model) followID(id logs.CorrelationID) (tea.Model, tea.Cmd) {
+	m.trace.choices = nil
+	m.trace.id = id
+	m.trace.loading = true
+	m.trace.events = nil
+	return m, m.searchCmd(id, m.traceTargets(m.trace.origin), m.trace.origin.Timestamp)
+}
+
+// traceTargets lists the groups to search per source: the configured
+// related-group set containing the origin group, or every selected group.
+func (m Model) traceTargets(origin logs.TailEvent) map[string][]string {
+	originSource := sourceKey(origin.Profile, origin.Region)
+	for _, set := range m.settings.RelatedGroups {
+		for _, g := range set {
+			if g == origin.LogGroup {
+				return map[string][]string{originSource: set}
+			}
+		}
+	}
+	return m.selectedBySource()
+}
+
+func (m Model) searchCmd(id logs.CorrelationID, targets map[string][]string, at time.Time) tea.Cmd {
+	sources := make([]source, 0, len(targets))
+	for _, key := range m.sourceOrder {
+		if len(targets[key]) > 0 {
+			sources = append(sources, m.sources[key])
+		}
+	}
+	start, end := at.Add(-correlationWindow), at.Add(correlationWindow)
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), searchTimeout)
+		defer cancel()
+		batches := make([][]logs.TailEvent, len(sources))
+		truncated := make([]bool, len(sources))
+		errs := make([]error, len(sources))
+		var wg sync.WaitGroup
+		for i, src := range sources {
+			wg.Add(1)
+			go func(i int, src source) {
+				defer wg.Done()
+				events, more, err := src.client.SearchEvents(ctx, targets[src.key()], id.FilterPattern(), start, end)
+				if errors.Is(err, context.DeadlineExceeded) {
+					truncated[i] = true
+					return
+				}
+				if err != nil {
+					errs[i] = fmt.Errorf("%s: %w", src.key(), err)
+					return
+				}
+				src.tagEvents(events)
+				batches[i] = events
+				truncated[i] = more
+			}(i, src)
+		}
+		wg.Wait()
+		return traceLoadedMsg{
+			id:        id,
+			events:    logs.MergeEvents(batches...),
+			truncated: slices.Contains(truncated, true),
+			err:       errors.Join(errs...),
+		}
+	}
+}
+
+func (m Model) updateTrace(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	t := &m.trace
+	if len(t.choices) > 0 {
+		switch msg.String() {
+		case "esc", "q":
+			t.active = false
+		case "up", "k":
+			if t.cursor > 0 {
+				t.cursor--
+			}
+		case "down", "j":
+			if t.cursor < len(t.choices)-1 {
+				t.cursor++
+			}
+		case "enter":
+			return m.followID(t.choices[t.cursor])
+		}
+		return m, nil
+	}
+	switch msg.String() {
+	case "esc", "q":
+		t.active = false
+		return m, nil
+	}
+	var cmd tea.Cmd
+	t.view, cmd = t.view.Update(msg)
+	return m, cmd
+}
+
+func (m *Model) applyTrace(msg traceLoadedMsg) {
+	if !m.trace.active || msg.id != m.trace.id {
+		return
+	}
+	m.trace.loading = false
+	if msg.err != nil {
+		m.statusLine = msg.err.Error()
+	}
+	m.trace.events = msg.events
+	m.trace.truncated = msg.truncated
+	groups := map[string]bool{}
+	for _, e := range msg.events {
+		groups[sourceKey(e.Profile, e.Region)+e.LogGroup] = true
+	}
+	m.trace.groups = len(groups)
+	now := time.Now()
+	lines := make([]string, 0, len(msg.events))
+	for _, e := range msg.events {
+		line := m.formatEvent(e, now)
+		if eventKey(e) == eventKey(m.trace.origin) {
+			line = m.styles.selected.Render(line)
+		}
+		lines = append(lines, line)
+	}
+	m.trace.view.SetContent(strings.Join(lines, "\n"))
+}
+
+func (m Model) renderTrace() string {
+	t := m.trace
+	var b strings.Builder
+	fmt.Fprintln(&b, m.styles.title.Render("Follow ID"))
+	if len(t.choices) > 0 {
+		fmt.Fprintln(&b, m.styles.dim.Render("several IDs found; enter to follow, esc to cancel"))
+		for i, id := range t.choices {
+			line := fmt.Sprintf("%-10s %s = %s", id.Kind, id.Name, id.Value)
+			if i == t.cursor {
+				line = m.styles.cursor.Render(line)
+			}
+			fmt.Fprintln(&b, line)
+		}
+		return b.String()
+	}
+	summary := fmt.Sprintf("%s %s", t.id.Name, t.id.Value)
+	if t.loading {
+		summary += " (searching...)"
+	} else {
+		summary += fmt.Sprintf(" — %d events in %d groups (±%s, esc close)", len(t.events), t.groups, correlationWindow)
+	}
+	fmt.Fprintln(&b, m.styles.dim.Render(summary))
+	if t.truncated {
+		fmt.Fprintln(&b, m.styles.warn.Render(fmt.Sprintf("partial: the search stopped at its page or %s limit; matches may be missing", searchTimeout)))
+	}
+	b.WriteString(t.view.View())
+	return b.String()
+}
@@ -6,43 +6,61 @@ import (
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/sachamama/sacha/internal/logs"
+	"github.com/sachamama/sacha/internal/theme"
 )
 
-var (
-	panelStyle = lipgloss.NewStyle().
-			Border(lipgloss.NormalBorder()).
-			Padding(0, 1)
-
-	titleStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("213")).
-			Bold(true)
-
-	cursorStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("229")).
-			Background(lipgloss.Color("57"))
-
-	selectedStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("51"))
-
-	dimText = lipgloss.NewStyle().
-		Foreground(lipgloss.Color("241"))
+// styles are the lipgloss styles of the view, built from the theme.
+type styles struct {
+	panel        lipgloss.Style
+	focusedPanel lipgloss.Style
+	title        lipgloss.Style
+	cursor       lipgloss.Style
+	selected     lipgloss.Style
+	dim          lipgloss.Style
+	status       lipgloss.Style
+	warn         lipgloss.Style
+	mark         lipgloss.Style
+}
 
-	statusStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("44"))
-)
+func newStyles(t *theme.Theme) styles {
+	panel := lipgloss.NewStyle().
+		Border(lipgloss.NormalBorder()).
+		Padding(0, 1)
+	s := styles{
+		panel:        panel,
+		focusedPanel: panel.BorderForeground(t.Border),
+		title:        t.TitleStyle(),
+		cursor:       t.CursorStyle(),
+		selected:     lipgloss.NewStyle().Foreground(t.Selected),
+		dim:          t.DimStyle(),
+		status:       lipgloss.NewStyle().Foreground(t.Status),
+		warn:         lipgloss.NewStyle().Foreground(t.Warn).Bold(true),
+		mark:         lipgloss.NewStyle().Foreground(t.Mark).Bold(true),
+	}
+	if t.Mono {
+		// Without colors the focused pane stands out by its heavier border.
+		s.focusedPanel = panel.Border(lipgloss.ThickBorder())
+		s.selected = s.selected.Bold(true)
+	}
+	return s
+}
 
 func (m Model) renderGroups() string {
 	b := &strings.Builder{}
-	header := titleStyle.Render("Log Groups")
+	header := m.styles.title.Render("Log Groups")
 	if m.loading {
-		header += " " + dimText.Render("(loading...)")
+		header += " " + m.styles.dim.Render("(loading...)")
 	}
 	fmt.Fprintln(b, header)
-	if m.searching {
+	switch {
+	case m.prompt != promptNone:
+		fmt.Fprintln(b, m.sourceInput.View())
+	case m.searching:
 		fmt.Fprintln(b, m.search.View())
-	} else {
-		fmt.Fprintln(b, "Press / to search")
+	default:
+		fmt.Fprintln(b, "Press "+m.keys.Keys("search")+" to search")
 	}
 	groups := m.filteredGroups()
 	if len(groups) == 0 {
@@ -50,38 +68,184 @@ func (m Model) renderGroups() string {
 		return b.String()
 	}
 
-	for i, g := range groups {
-		line := fmt.Sprintf("[%s] %s", checkbox(m.selected[g.Name]), g.Name)
+	multi := m.multiSource()
+	start := m.groupStart()
+	for i := start; i < len(groups) && i < start+m.groupRows(); i++ {
+		g := groups[i]
+		selected := m.selected[refOf(g)]
+		line := fmt.Sprintf("[%s] %s", checkbox(selected), g.Name)
+		if multi {
+			line += " " + m.styles.dim.Render(m.sourceLabel(g.Profile, g.Region))
+		}
 		if i == m.cursor {
-			line = cursorStyle.Render(line)
+			line = m.styles.cursor.Render(line)
 		}
-		if m.selected[g.Name] {
-			line = selectedStyle.Render(line)
+		if selected {
+			line = m.styles.selected.Render(line)
 		}
 		fmt.Fprintln(b, line)
 	}
 
-	fmt.Fprintf(b, "\n%s\n", dimText.Render(fmt.Sprintf("Selected: %d | Total: %d", m.selectedCount(), len(m.logGroups))))
+	fmt.Fprintf(b, "\n%s\n", m.styles.dim.Render(fmt.Sprintf("Selected: %d | Total: %d", m.selectedCount(), len(m.logGroups))))
 	if m.statusLine != "" {
-		fmt.Fprintf(b, "%s\n", statusStyle.Render(m.statusLine))
+		fmt.Fprintf(b, "%s\n", m.styles.status.Render(m.statusLine))
 	}
 	return b.String()
 }
 
 func (m Model) renderTail() string {
+	if m.historyView.active {
+		return m.renderHistory()
+	}
+	if m.links.active {
+		return m.renderLinks()
+	}
 	if !m.tailing {
-		return fmt.Sprintf("%s\n%s", titleStyle.Render("Tail"), dimText.Render("Press t to start tailing selected groups"))
+		return fmt.Sprintf("%s\n%s", m.styles.title.Render("Tail"), m.styles.dim.Render("Press "+m.keys.Keys("tail")+" to start tailing selected groups"))
+	}
+	if m.bookmarks.active {
+		return m.renderBookmarks()
+	}
+	if m.stats.active {
+		return m.renderStats()
+	}
+	if m.trace.active {
+		return m.renderTrace()
+	}
+	if m.invocations.active {
+		return m.renderInvocations()
+	}
+	header := fmt.Sprintf("%s %s", m.styles.title.Render("Tail"), m.styles.dim.Render(fmt.Sprintf("(%s, %s)", m.times.label(), m.keys.Hints(
+		"time", "time", "lag", "lag", "filter", "filter", "fields", "fields", "tail-down", "down", "tail-up", "up",
+		"mark", "mark", "bookmarks", "marks", "copy", "copy", "links", "links", "follow-id", "follow ID", "lambda", "lambda",
+		"page-down", "scroll", "stop", "stop"))))
+	switch {
+	case m.bookmarks.noting:
+		header = m.bookmarks.input.View()
+	case m.filtering:
+		header = m.filterInput.View()
+	case !m.filter.Empty():
+		header = fmt.Sprintf("%s %s", m.styles.title.Render("Tail"), m.styles.status.Render("filter: "+m.filter.String()))
+	}
+	// The header must stay on one line; setViewportSize counts on it.
+	header = lipgloss.NewStyle().MaxWidth(m.view.Width).Render(header)
+	if lag := m.renderLagSummary(); lag != "" {
+		header += "\n" + lipgloss.NewStyle().MaxWidth(m.view.Width).Render(lag)
 	}
-	header := fmt.Sprintf("%s %s", titleStyle.Render("Tail"), dimText.Render("(pgup/pgdn scroll, q/esc stop)"))
 	return fmt.Sprintf("%s\n%s", header, m.view.View())
 }
 
-func renderEvents(events []logs.TailEvent) string {
+// wrapIndent is the hanging indent of wrapped rows.
+const wrapIndent = 4
+
+// renderEvents returns the tail content and the first line of each event.
+// Multi-line events and repeated lines take one line until expanded; long
+// lines wrap in wrap mode.
+func (m Model) renderEvents(events []logs.TailEvent, cursor int) (string, []int) {
 	var b strings.Builder
-	for _, e := range events {
-		fmt.Fprintf(&b, "%s | %s | %s\n", e.Timestamp.Format(time.RFC3339), e.LogGroup, strings.TrimSpace(e.Message))
+	now := time.Now()
+	marked := make(map[string]bool, len(m.marks))
+	for _, mark := range m.marks {
+		marked[eventKey(mark.Event)] = true
 	}
-	return b.String()
+	lines, starts := 0, make([]int, len(events))
+	for i, e := range events {
+		key := eventKey(e)
+		starts[i] = lines
+		var rest []string
+		if m.collapsible(e) {
+			e, rest = m.collapse(e, now)
+		}
+		gutter := ""
+		if marked[key] {
+			gutter = "◆ "
+		}
+		for n, row := range m.wrapRows(m.formatEvent(e, now), m.view.Width-len([]rune(gutter))) {
+			if i == cursor && !m.follow {
+				row = m.styles.cursor.Render(row)
+			}
+			switch {
+			case gutter == "":
+			case n == 0:
+				row = m.styles.mark.Render(gutter) + row
+			default:
+				row = "  " + row
+			}
+			fmt.Fprintln(&b, row)
+			lines++
+		}
+		for _, r := range rest {
+			for _, row := range m.wrapRows(r, m.view.Width-6) {
+				fmt.Fprintln(&b, m.styles.dim.Render("    │ ")+row)
+				lines++
+			}
+		}
+	}
+	return b.String(), starts
+}
+
+// wrapRows splits a plain line into display rows. Embedded newlines always
+// start a row; in wrap mode rows wider than width continue below with a
+// hanging indent, otherwise the viewport scrolls horizontally.
+func (m Model) wrapRows(s string, width int) []string {
+	var rows []string
+	// Expand tabs as lipgloss draws them, so widths add up.
+	s = strings.ReplaceAll(s, "\t", "    ")
+	for _, line := range strings.Split(s, "\n") {
+		if !m.wrap || width <= wrapIndent+2 || ansi.StringWidth(line) <= width {
+			rows = append(rows, line)
+			continue
+		}
+		rows = append(rows, ansi.Truncate(line, width, ""))
+		line = ansi.TruncateLeft(line, width, "")
+		for line != "" {
+			rows = append(rows, strings.Repeat(" ", wrapIndent)+ansi.Truncate(line, width-wrapIndent, ""))
+			line = ansi.TruncateLeft(line, width-wrapIndent, "")
+		}
+	}
+	return rows
+}
+
+// collapse returns the summary line of an expandable event, and the lines
+// below it when expanded: each occurrence of a repeated line, or the
+// continuation lines of a joined event.
+func (m Model) collapse(e logs.TailEvent, now time.Time) (logs.TailEvent, []string) {
+	first, more, _ := strings.Cut(strings.TrimSpace(e.Message), "\n")
+	open := m.expanded[eventKey(e)]
+	var rest []string
+	switch {
+	case len(e.Repeats) > 1:
+		last := e.Repeats[len(e.Repeats)-1].Timestamp
+		e.Message = fmt.Sprintf("×%d until %s %s", len(e.Repeats), m.times.format(last, now), first)
+		if open {
+			for _, r := range e.Repeats {
+				msg, _, _ := strings.Cut(strings.TrimSpace(r.Message), "\n")
+				rest = append(rest, m.times.format(r.Timestamp, now)+" "+msg)
+			}
+		}
+	case open:
+		e.Message = first
+		rest = strings.Split(more, "\n")
+	default:
+		e.Message = fmt.Sprintf("(+%d) %s", strings.Count(more, "\n")+1, first)
+	}
+	marker := "▸ "
+	if open {
+		marker = "▾ "
+	}
+	e.Message = marker + e.Message
+	return e, rest
+}
+
+func (m Model) formatEvent(e logs.TailEvent, now time.Time) string {
+	ts := m.times.format(e.Timestamp, now)
+	if m.showLag {
+		ts += " (+" + formatDuration(e.Lag()) + ")"
+	}
+	if m.multiSource() {
+		return fmt.Sprintf("%s | %s | %s | %s", ts, m.sourceLabel(e.Profile, e.Region), e.LogGroup, strings.TrimSpace(e.Message))
+	}
+	return fmt.Sprintf("%s | %s | %s", ts, e.LogGroup, strings.TrimSpace(e.Message))
 }
 
 func checkbox(selected bool) string {
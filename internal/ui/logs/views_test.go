@@ -0,0 +1,39 @@
+package logs
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestWrapRows(t *testing.T) {
+	cases := []struct {
+		name  string
+		wrap  bool
+		in    string
+		width int
+		want  []string
+	}{
+		{"scrolls when wrap is off", false, "abcdefghijkl", 8, []string{"abcdefghijkl"}},
+		{"fits", true, "abcdefgh", 8, []string{"abcdefgh"}},
+		{"hanging indent", true, "abcdefghijklmnop", 8, []string{"abcdefgh", "    ijkl", "    mnop"}},
+		{"newlines start rows", false, "first\nsecond", 8, []string{"first", "second"}},
+		{"tabs expand", true, "a\tb\tcdef", 8, []string{"a    b  ", "      cd", "    ef"}},
+		{"wide runes", true, "日本語のテキスト", 8, []string{"日本語の", "    テキ", "    スト"}},
+		{"too narrow to indent", true, "abcdefghijkl", 6, []string{"abcdefghijkl"}},
+	}
+	for _, tc := range cases {
+		got := Model{wrap: tc.wrap}.wrapRows(tc.in, tc.width)
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Fatalf("%s: got %q, want %q", tc.name, got, tc.want)
+		}
+		if tc.wrap {
+			for _, row := range got {
+				if w := ansi.StringWidth(row); w > tc.width && tc.width > wrapIndent+2 {
+					t.Fatalf("%s: row %q is %d wide, over %d", tc.name, row, w, tc.width)
+				}
+			}
+		}
+	}
+}
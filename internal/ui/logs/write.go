@@ -0,0 +1,303 @@
+package logs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sachamama/sacha/internal/logs"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	defaultWriteStream = "sacha-test"
+	streamSuggestions  = 50
+	maxWriteCopies     = 100
+)
+
+// writePanel sends test events to a log group with PutLogEvents.
+type writePanel struct {
+	active  bool
+	source  string
+	group   textinput.Model
+	stream  textinput.Model
+	copies  textinput.Model
+	message textarea.Model
+	focus   int
+	sending bool
+}
+
+type streamsLoadedMsg struct {
+	group   string
+	streams []logs.LogStream
+	err     error
+}
+
+type eventsWrittenMsg struct {
+	target string
+	count  int
+	err    error
+}
+
+type editorDoneMsg struct {
+	text string
+	err  error
+}
+
+func newWritePanel() writePanel {
+	group := textinput.New()
+	group.Prompt = "log group: "
+	stream := textinput.New()
+	stream.Prompt = "stream: "
+	stream.Placeholder = defaultWriteStream
+	stream.ShowSuggestions = true
+	stream.KeyMap.AcceptSuggestion = key.NewBinding(key.WithKeys("right"))
+	copies := textinput.New()
+	copies.Prompt = "copies: "
+	message := textarea.New()
+	message.Placeholder = `plain text, or a JSON payload such as {"level":"error","statusCode":500}`
+	message.ShowLineNumbers = false
+	return writePanel{group: group, stream: stream, copies: copies, message: message}
+}
+
+// openWrite targets the group under the cursor, which must belong to a
+// source that takes new events.
+func (m *Model) openWrite() tea.Cmd {
+	groups := m.filteredGroups()
+	if len(groups) == 0 || m.cursor >= len(groups) {
+		return nil
+	}
+	ref := refOf(groups[m.cursor])
+	client, ok := m.sources[ref.source].client.(logs.Writer)
+	if !ok {
+		m.statusLine = unsupported("writing events").Error()
+		return nil
+	}
+	w := &m.write
+	w.active = true
+	w.source = ref.source
+	if w.group.Value() != ref.name {
+		w.group.SetValue(ref.name)
+		w.stream.SetValue(defaultWriteStream)
+		w.stream.SetSuggestions(nil)
+	}
+	if w.copies.Value() == "" {
+		w.copies.SetValue("1")
+	}
+	w.message.SetWidth(max(m.width-8, 20))
+	w.message.SetHeight(max(m.bodyHeight()-12, 3))
+	w.focus = 3
+	return tea.Batch(w.focusField(), listStreamsCmd(client, ref.name))
+}
+
+func listStreamsCmd(client logs.Writer, group string) tea.Cmd {
+	return func() tea.Msg {
+		streams, err := client.ListLogStreams(context.Background(), group, streamSuggestions)
+		return streamsLoadedMsg{group: group, streams: streams, err: err}
+	}
+}
+
+func (w *writePanel) focusField() tea.Cmd {
+	w.group.Blur()
+	w.stream.Blur()
+	w.copies.Blur()
+	w.message.Blur()
+	switch w.focus {
+	case 0:
+		return w.group.Focus()
+	case 1:
+		return w.stream.Focus()
+	case 2:
+		return w.copies.Focus()
+	}
+	return w.message.Focus()
+}
+
+// updateWriteMsg handles async results for the panel.
+func (m *Model) updateWriteMsg(msg tea.Msg) tea.Cmd {
+	w := &m.write
+	switch msg := msg.(type) {
+	case streamsLoadedMsg:
+		if msg.err != nil {
+			m.statusLine = msg.err.Error()
+			return nil
+		}
+		if msg.group != w.group.Value() {
+			return nil
+		}
+		names := make([]string, 0, len(msg.streams))
+		for _, s := range msg.streams {
+			names = append(names, s.Name)
+		}
+		w.stream.SetSuggestions(names)
+	case eventsWrittenMsg:
+		w.sending = false
+		if msg.err != nil {
+			m.statusLine = msg.err.Error()
+			return nil
+		}
+		m.statusLine = fmt.Sprintf("sent %d event(s) to %s", msg.count, msg.target)
+	case editorDoneMsg:
+		if msg.err != nil {
+			m.statusLine = "editor: " + msg.err.Error()
+			return nil
+		}
+		w.message.SetValue(msg.text)
+	}
+	return nil
+}
+
+func (m Model) updateWrite(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	w := &m.write
+	switch msg.String() {
+	case "esc":
+		w.active = false
+		return m, nil
+	case "tab":
+		w.focus = (w.focus + 1) % 4
+		return m, w.focusField()
+	case "shift+tab":
+		w.focus = (w.focus + 3) % 4
+		return m, w.focusField()
+	case "ctrl+e":
+		return m, openEditor(w.message.Value())
+	case "ctrl+s":
+		if w.sending {
+			return m, nil
+		}
+		cmd, err := m.sendEventsCmd()
+		if err != nil {
+			m.statusLine = err.Error()
+			return m, nil
+		}
+		w.sending = true
+		return m, cmd
+	}
+	var cmd tea.Cmd
+	switch w.focus {
+	case 0:
+		w.group, cmd = w.group.Update(msg)
+	case 1:
+		w.stream, cmd = w.stream.Update(msg)
+	case 2:
+		w.copies, cmd = w.copies.Update(msg)
+	default:
+		w.message, cmd = w.message.Update(msg)
+	}
+	return m, cmd
+}
+
+func (m Model) sendEventsCmd() (tea.Cmd, error) {
+	w := m.write
+	src, ok := m.sources[w.source]
+	if !ok {
+		return nil, fmt.Errorf("%s is no longer loaded", w.source)
+	}
+	client, ok := src.client.(logs.Writer)
+	if !ok {
+		return nil, unsupported("writing events")
+	}
+	group := strings.TrimSpace(w.group.Value())
+	stream := strings.TrimSpace(w.stream.Value())
+	if stream == "" {
+		stream = defaultWriteStream
+	}
+	copies, err := strconv.Atoi(strings.TrimSpace(w.copies.Value()))
+	if err != nil || copies < 1 || copies > maxWriteCopies {
+		return nil, fmt.Errorf("copies must be between 1 and %d", maxWriteCopies)
+	}
+	message, err := payload(w.message.Value())
+	if err != nil {
+		return nil, err
+	}
+	messages := make([]string, copies)
+	for i := range messages {
+		messages[i] = message
+	}
+	target := group + "/" + stream
+	return func() tea.Msg {
+		err := client.PutEvents(context.Background(), group, stream, messages, time.Now())
+		return eventsWrittenMsg{target: target, count: copies, err: err}
+	}, nil
+}
+
+// payload validates and compacts JSON so metric filters see one line; other
+// text is sent as typed.
+func payload(text string) (string, error) {
+	text = strings.TrimRight(text, "\n")
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" {
+		return "", fmt.Errorf("message is empty")
+	}
+	if trimmed[0] != '{' && trimmed[0] != '[' {
+		return text, nil
+	}
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, []byte(trimmed)); err != nil {
+		return "", fmt.Errorf("invalid JSON payload: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// openEditor edits text in $VISUAL or $EDITOR, suspending the TUI meanwhile.
+func openEditor(text string) tea.Cmd {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+	f, err := os.CreateTemp("", "sacha-event-*.json")
+	if err != nil {
+		return func() tea.Msg { return editorDoneMsg{err: err} }
+	}
+	path := f.Name()
+	_, err = f.WriteString(text)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(path)
+		return func() tea.Msg { return editorDoneMsg{err: err} }
+	}
+	args := append(strings.Fields(editor), path)
+	cmd := exec.Command(args[0], args[1:]...)
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		defer os.Remove(path)
+		if err != nil {
+			return editorDoneMsg{err: err}
+		}
+		data, err := os.ReadFile(path)
+		return editorDoneMsg{text: string(data), err: err}
+	})
+}
+
+func (m Model) renderWrite() string {
+	w := m.write
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s\n", m.styles.title.Render("Write test events"), m.styles.dim.Render(w.source))
+	fmt.Fprintln(&b, m.styles.dim.Render("tab next field, → accept stream suggestion, ctrl+e $EDITOR, ctrl+s send, esc close"))
+	fmt.Fprintln(&b, w.group.View())
+	fmt.Fprintln(&b, w.stream.View())
+	fmt.Fprintln(&b, w.copies.View())
+	fmt.Fprintln(&b, w.message.View())
+	fmt.Fprintln(&b, m.styles.dim.Render("JSON payloads are validated and sent as one compact line; missing streams are created"))
+	if w.sending {
+		fmt.Fprintln(&b, m.styles.dim.Render("sending..."))
+	}
+	if m.statusLine != "" {
+		fmt.Fprintf(&b, "\n%s\n", m.styles.status.Render(m.statusLine))
+	}
+	return b.String()
+}